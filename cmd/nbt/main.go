@@ -0,0 +1,147 @@
+// Command nbt converts NBT files between binary NBT, SNBT and a JSON representation that keeps tag types.
+//
+// Usage:
+//
+//	nbt [flags] <input> <output>
+//
+// The input and output may be - to read from stdin or write to stdout. The formats are derived from the file
+// extensions (.json for JSON, .snbt for SNBT and anything else for binary NBT) unless set using flags. Binary
+// input may be compressed using gzip or zlib, which is detected automatically.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/justtaldevelops/expresso/expresso/nbt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	from := flag.String("from", "", "format of the input: binary, snbt or json (derived from the extension by default)")
+	to := flag.String("to", "", "format of the output: binary, snbt or json (derived from the extension by default)")
+	compression := flag.String("compression", "", "compression of binary output: none, gzip or zlib (that of the input, or gzip, by default)")
+	name := flag.String("name", "", "name of the root tag of binary or JSON output (that of the input by default)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %v [flags] <input> <output>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := convert(flag.Arg(0), flag.Arg(1), *from, *to, *compression, *name); err != nil {
+		fmt.Fprintln(os.Stderr, "nbt:", err)
+		os.Exit(1)
+	}
+}
+
+// convert converts the file at the input path to the output path using the formats, compression and root name
+// passed. Empty values are derived from the paths and the input.
+func convert(in, out, from, to, compression, name string) error {
+	if from == "" {
+		from = formatFromPath(in)
+	}
+	if to == "" {
+		to = formatFromPath(out)
+	}
+
+	data, err := readInput(in)
+	if err != nil {
+		return err
+	}
+	var (
+		t    nbt.Tag
+		info = nbt.FileInfo{Compression: nbt.CompressionGzip}
+	)
+	switch from {
+	case "binary":
+		info, err = nbt.Read(bytes.NewReader(data), &t)
+	case "snbt":
+		err = nbt.UnmarshalSNBT(bytes.TrimSpace(data), &t)
+	case "json":
+		info.Name, t, err = nbt.UnmarshalJSON(data)
+	default:
+		return fmt.Errorf("unknown input format %q", from)
+	}
+	if err != nil {
+		return fmt.Errorf("read %v: %w", in, err)
+	}
+	if name != "" {
+		info.Name = name
+	}
+	if compression != "" {
+		if info.Compression, err = parseCompression(compression); err != nil {
+			return err
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	switch to {
+	case "binary":
+		err = nbt.Write(buf, t, info)
+	case "snbt":
+		var s string
+		s, err = nbt.MarshalSNBT(t)
+		buf.WriteString(s + "\n")
+	case "json":
+		var b []byte
+		if b, err = nbt.MarshalJSON(info.Name, t); err == nil {
+			// Indent the JSON so that changes to it produce readable diffs.
+			err = json.Indent(buf, b, "", "  ")
+			buf.WriteByte('\n')
+		}
+	default:
+		return fmt.Errorf("unknown output format %q", to)
+	}
+	if err != nil {
+		return fmt.Errorf("write %v: %w", out, err)
+	}
+	return writeOutput(out, buf.Bytes())
+}
+
+// formatFromPath returns the format of a file based on the extension of its path.
+func formatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".snbt":
+		return "snbt"
+	}
+	return "binary"
+}
+
+// parseCompression parses the name of a compression algorithm.
+func parseCompression(s string) (nbt.Compression, error) {
+	switch s {
+	case "none":
+		return nbt.CompressionNone, nil
+	case "gzip":
+		return nbt.CompressionGzip, nil
+	case "zlib":
+		return nbt.CompressionZlib, nil
+	}
+	return 0, fmt.Errorf("unknown compression %q", s)
+}
+
+// readInput reads all data from the path passed, or from stdin if the path is -.
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// writeOutput writes the data passed to the path passed, or to stdout if the path is -.
+func writeOutput(path string, data []byte) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
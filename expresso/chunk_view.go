@@ -0,0 +1,214 @@
+package expresso
+
+import (
+	"fmt"
+	"github.com/justtaldevelops/expresso/expresso/protocol"
+	"github.com/justtaldevelops/expresso/expresso/protocol/packet"
+	"math"
+	"sync"
+)
+
+// ChunkSource provides the chunk columns that are sent to connections by a ChunkView.
+type ChunkSource interface {
+	// Column returns the column at the position passed.
+	Column(pos protocol.ColumnPos) (*protocol.Column, error)
+}
+
+// ChunkSourceFunc is a function that implements ChunkSource.
+type ChunkSourceFunc func(pos protocol.ColumnPos) (*protocol.Column, error)
+
+// Column ...
+func (f ChunkSourceFunc) Column(pos protocol.ColumnPos) (*protocol.Column, error) {
+	return f(pos)
+}
+
+// ChunkView keeps track of the chunk columns loaded by a single connection. It streams in the columns around
+// the player as it moves, and unloads the columns that went out of range.
+type ChunkView struct {
+	conn   *Connection
+	source ChunkSource
+
+	maxViewDistance int32
+
+	mu           sync.Mutex
+	moved        bool
+	center       protocol.ColumnPos
+	viewDistance int32
+	loaded       map[protocol.ColumnPos]struct{}
+}
+
+// NewChunkView returns a new ChunkView for the connection passed, which loads columns from the source passed.
+// The view distance of the connection never exceeds the maximum view distance passed, even if the client
+// requests a larger one.
+func NewChunkView(conn *Connection, source ChunkSource, maxViewDistance int32) *ChunkView {
+	return &ChunkView{
+		conn:            conn,
+		source:          source,
+		maxViewDistance: maxViewDistance,
+		loaded:          make(map[protocol.ColumnPos]struct{}),
+	}
+}
+
+// HandlePacket updates the view if the packet passed is relevant to it. Client settings update the view
+// distance of the view, and player movement packets update the center of the view.
+func (v *ChunkView) HandlePacket(pk packet.Packet) error {
+	switch pk := pk.(type) {
+	case *packet.ClientSettings:
+		return v.Refresh()
+	case *packet.ClientPlayerPosition:
+		return v.Move(columnPosFromWorld(pk.X, pk.Z))
+	case *packet.ClientPlayerPositionRotation:
+		return v.Move(columnPosFromWorld(pk.X, pk.Z))
+	}
+	return nil
+}
+
+// Move moves the center of the view to the column passed. If the center changed, the client is notified and
+// columns that are now out of range are unloaded, after which all missing columns in range are sent.
+func (v *ChunkView) Move(center protocol.ColumnPos) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.moved && v.center == center {
+		return nil
+	}
+	v.moved, v.center = true, center
+	if err := v.conn.WritePacket(&packet.UpdateViewPosition{X: center.X(), Z: center.Z()}); err != nil {
+		return err
+	}
+	return v.update()
+}
+
+// Refresh re-evaluates the view distance of the view using the latest client settings, loading or unloading
+// columns if it has changed.
+func (v *ChunkView) Refresh() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if !v.moved || v.viewDistance == v.effectiveViewDistance() {
+		return nil
+	}
+	return v.update()
+}
+
+// Center returns the column the view is currently centered on.
+func (v *ChunkView) Center() protocol.ColumnPos {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.center
+}
+
+// ViewDistance returns the view distance currently used by the view.
+func (v *ChunkView) ViewDistance() int32 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.viewDistance
+}
+
+// Loaded returns true if the column at the position passed is loaded by the client.
+func (v *ChunkView) Loaded(pos protocol.ColumnPos) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	_, ok := v.loaded[pos]
+	return ok
+}
+
+// Resend sends the column at the position passed to the client again if it is loaded, for example after many
+// of its blocks have changed.
+func (v *ChunkView) Resend(pos protocol.ColumnPos) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if _, ok := v.loaded[pos]; !ok {
+		return nil
+	}
+	return v.send(pos)
+}
+
+// Clear unloads all columns loaded by the client.
+func (v *ChunkView) Clear() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for pos := range v.loaded {
+		if err := v.conn.WritePacket(&packet.UnloadChunk{X: pos.X(), Z: pos.Z()}); err != nil {
+			return err
+		}
+		delete(v.loaded, pos)
+	}
+	v.moved = false
+	return nil
+}
+
+// update unloads all columns that are out of range and sends all missing columns in range, from the center
+// outwards.
+func (v *ChunkView) update() error {
+	v.viewDistance = v.effectiveViewDistance()
+	for pos := range v.loaded {
+		if !v.inRange(pos) {
+			if err := v.conn.WritePacket(&packet.UnloadChunk{X: pos.X(), Z: pos.Z()}); err != nil {
+				return err
+			}
+			delete(v.loaded, pos)
+		}
+	}
+
+	for _, pos := range spiral(v.center, v.viewDistance) {
+		if _, ok := v.loaded[pos]; ok {
+			continue
+		}
+		if err := v.send(pos); err != nil {
+			return err
+		}
+		v.loaded[pos] = struct{}{}
+	}
+	return nil
+}
+
+// send sends the column at the position passed to the client.
+func (v *ChunkView) send(pos protocol.ColumnPos) error {
+	column, err := v.source.Column(pos)
+	if err != nil {
+		return fmt.Errorf("load column %v: %w", pos, err)
+	}
+	return v.conn.WritePacket(&packet.ChunkData{Column: column})
+}
+
+// inRange returns true if the column at the position passed is within the view distance of the center.
+func (v *ChunkView) inRange(pos protocol.ColumnPos) bool {
+	dx, dz := pos.X()-v.center.X(), pos.Z()-v.center.Z()
+	return dx >= -v.viewDistance && dx <= v.viewDistance && dz >= -v.viewDistance && dz <= v.viewDistance
+}
+
+// effectiveViewDistance returns the view distance requested by the client, limited by the maximum view distance
+// of the view. If the client has not requested a view distance yet, the maximum view distance is returned.
+func (v *ChunkView) effectiveViewDistance() int32 {
+	requested := int32(v.conn.ClientSettings().ViewDistance)
+	if requested <= 0 || requested > v.maxViewDistance {
+		return v.maxViewDistance
+	}
+	return requested
+}
+
+// spiral returns all column positions within the radius passed around the center, ordered in a spiral from the
+// center outwards.
+func spiral(center protocol.ColumnPos, radius int32) []protocol.ColumnPos {
+	positions := make([]protocol.ColumnPos, 0, (2*radius+1)*(2*radius+1))
+	positions = append(positions, center)
+	for r := int32(1); r <= radius; r++ {
+		x, z := center.X()-r, center.Z()-r
+		for _, dir := range [4][2]int32{{1, 0}, {0, 1}, {-1, 0}, {0, -1}} {
+			for i := int32(0); i < 2*r; i++ {
+				positions = append(positions, protocol.ColumnPos{x, z})
+				x, z = x+dir[0], z+dir[1]
+			}
+		}
+	}
+	return positions
+}
+
+// columnPosFromWorld returns the position of the column that the world X and Z passed are in.
+func columnPosFromWorld(x, z float64) protocol.ColumnPos {
+	return protocol.ColumnPos{int32(math.Floor(x)) >> 4, int32(math.Floor(z)) >> 4}
+}
@@ -33,6 +33,11 @@ type Connection struct {
 
 	packetState atomic.Value
 
+	settings atomic.Value
+
+	name string
+	id   uuid.UUID
+
 	reader *protocol.Reader
 	writer *protocol.Writer
 
@@ -54,6 +59,7 @@ func newConn(listener *Listener, netConn net.Conn) {
 		writer: protocol.NewWriter(netConn),
 	}
 	conn.updateState(packet.StateHandshaking())
+	conn.settings.Store(packet.ClientSettings{})
 
 	go conn.startReading()
 }
@@ -71,8 +77,26 @@ func (c *Connection) Disconnect(reason text.Text) {
 
 // Close closes the connection.
 func (c *Connection) Close() {
-	c.closed.Store(true)
+	if c.closed.Swap(true) {
+		return
+	}
 	_ = c.conn.Close()
+	c.listener.removeConnection(c)
+}
+
+// Name returns the username of the player of the connection. It is empty until the player has logged in.
+func (c *Connection) Name() string {
+	return c.name
+}
+
+// UUID returns the UUID of the player of the connection. It is the zero UUID until the player has logged in.
+func (c *Connection) UUID() uuid.UUID {
+	return c.id
+}
+
+// RemoteAddr returns the address of the client of the connection.
+func (c *Connection) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
 }
 
 // WritePacket writes a packet to the connection.
@@ -126,6 +150,12 @@ func (c *Connection) Compression() bool {
 	return c.CompressionThreshold() > 0
 }
 
+// ClientSettings returns the latest settings sent by the client. If the client has not sent its settings yet,
+// the zero value is returned.
+func (c *Connection) ClientSettings() packet.ClientSettings {
+	return c.settings.Load().(packet.ClientSettings)
+}
+
 // readPacket reads a packet from a connection.
 func (c *Connection) readPacket() (packet.Packet, error) {
 	// Decode the newest packet from the connection.
@@ -145,7 +175,7 @@ func (c *Connection) readPacket() (packet.Packet, error) {
 
 	if ok, err := c.handlePacket(pk); ok {
 		if err != nil {
-			c.Disconnect(text.Text{Text: err.Error(), Color: "red"})
+			c.Disconnect(text.Text{Text: err.Error(), Color: text.Red})
 			return nil, fmt.Errorf("read packet when connection closed: %w", err)
 		}
 
@@ -211,6 +241,10 @@ func (c *Connection) handlePacket(pk packet.Packet) (bool, error) {
 	case *packet.ClientKeepAlive:
 		c.lastKeepAlive.Store(time.Now().Unix())
 		return true, nil
+	case *packet.ClientSettings:
+		// The settings are stored, but the packet is still passed on so that it may be handled further.
+		c.settings.Store(*pk)
+		return false, nil
 	case *packet.Handshake:
 		return c.handleHandshake(pk)
 	}
@@ -222,7 +256,7 @@ func (c *Connection) handlePacket(pk packet.Packet) (bool, error) {
 func (c *Connection) handleHandshake(pk *packet.Handshake) (bool, error) {
 	switch pk.NextState {
 	case 0x01:
-		return c.handlePing()
+		return c.handlePing(pk)
 	case 0x02:
 		// Make sure we support the protocol version.
 		if pk.Protocol > protocol.CurrentProtocol {
@@ -239,8 +273,8 @@ func (c *Connection) handleHandshake(pk *packet.Handshake) (bool, error) {
 	return false, nil
 }
 
-// handlePing handles the server list ping sequence.
-func (c *Connection) handlePing() (bool, error) {
+// handlePing handles the server list ping sequence that follows the handshake passed.
+func (c *Connection) handlePing(handshake *packet.Handshake) (bool, error) {
 	c.updateState(packet.StateStatus())
 
 	for i := 0; i < 2; i++ {
@@ -253,7 +287,13 @@ func (c *Connection) handlePing() (bool, error) {
 		// Handle the part of the sequence we are in.
 		switch pk := pk.(type) {
 		case *packet.ClientStatusRequest:
-			if err = c.WritePacket(&packet.ServerStatusResponse{Status: c.listener.Status().String()}); err != nil {
+			status := c.listener.Status(StatusRequest{
+				RemoteAddr: c.RemoteAddr(),
+				Protocol:   handshake.Protocol,
+				Address:    handshake.Address,
+				Port:       uint16(handshake.Port),
+			})
+			if err = c.WritePacket(&packet.ServerStatusResponse{Status: status.String()}); err != nil {
 				return true, err
 			}
 		case *packet.ClientStatusPing:
@@ -358,7 +398,9 @@ func (c *Connection) handleLogin() (bool, error) {
 	}
 
 	// Play packets can now be used, so we can add it to the listener now.
+	c.name, c.id = loginStart.Username, uuidForResponse
 	c.updateState(packet.StatePlay())
+	c.listener.addConnection(c)
 	c.listener.incoming <- c
 
 	go c.keepAlive()
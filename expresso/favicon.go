@@ -0,0 +1,86 @@
+package expresso
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+)
+
+// FaviconSize is the width and height in pixels of the favicon of a server.
+const FaviconSize = 64
+
+// faviconPrefix is the prefix of the favicon in the status, which is followed by the base64 encoded PNG.
+const faviconPrefix = "data:image/png;base64,"
+
+// LoadFavicon loads the PNG file at the path passed and returns it as favicon for use in a Status using
+// FaviconFromPNG.
+func LoadFavicon(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return FaviconFromPNG(data)
+}
+
+// FaviconFromPNG returns the PNG data passed as favicon for use in a Status. An error is returned if the data
+// is not a PNG image of FaviconSize by FaviconSize pixels.
+func FaviconFromPNG(data []byte) (string, error) {
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("decode favicon: %w", err)
+	}
+	if cfg.Width != FaviconSize || cfg.Height != FaviconSize {
+		return "", fmt.Errorf("favicon must be %vx%v pixels, got %vx%v", FaviconSize, FaviconSize, cfg.Width, cfg.Height)
+	}
+	return faviconPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// FaviconFromImage encodes the image passed as favicon for use in a Status. Images that are not FaviconSize by
+// FaviconSize pixels are resized first. An error is returned if the image is empty.
+func FaviconFromImage(img image.Image) (string, error) {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return "", fmt.Errorf("favicon image is empty")
+	}
+	if bounds.Dx() != FaviconSize || bounds.Dy() != FaviconSize {
+		img = resizeFavicon(img)
+	}
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		return "", fmt.Errorf("encode favicon: %w", err)
+	}
+	return faviconPrefix + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// resizeFavicon resizes the image passed to FaviconSize by FaviconSize pixels. Every pixel of the resized image
+// is the average of the pixels of the image it covers, or the nearest pixel if the image is enlarged.
+func resizeFavicon(img image.Image) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	resized := image.NewRGBA64(image.Rect(0, 0, FaviconSize, FaviconSize))
+	for y := 0; y < FaviconSize; y++ {
+		minY, maxY := y*h/FaviconSize, (y+1)*h/FaviconSize
+		if maxY == minY {
+			maxY++
+		}
+		for x := 0; x < FaviconSize; x++ {
+			minX, maxX := x*w/FaviconSize, (x+1)*w/FaviconSize
+			if maxX == minX {
+				maxX++
+			}
+			var r, g, b, a, n uint64
+			for sy := minY; sy < maxY; sy++ {
+				for sx := minX; sx < maxX; sx++ {
+					sr, sg, sb, sa := img.At(bounds.Min.X+sx, bounds.Min.Y+sy).RGBA()
+					r, g, b, a, n = r+uint64(sr), g+uint64(sg), b+uint64(sb), a+uint64(sa), n+1
+				}
+			}
+			resized.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return resized
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"sync"
 )
 
 // ListenConfig configures certain parts of the listener.
@@ -19,6 +20,9 @@ type ListenConfig struct {
 	DisableAuthentication bool
 	// StatusProvider represents the server list status which is displayed on the multiplayer screen.
 	StatusProvider StatusProvider
+	// Query configures the Query protocol, which is answered over UDP on the same port as the listener if
+	// Query is not nil.
+	Query *QueryConfig
 }
 
 // Listener is an Expresso listener. It listens on TCP for Minecraft packets, decodes them, and allows
@@ -35,6 +39,11 @@ type Listener struct {
 
 	status atomic.Value
 
+	query *QueryListener
+
+	connMu      sync.Mutex
+	connections map[*Connection]struct{}
+
 	keyPair     *rsa.PrivateKey
 	verifyToken []byte
 }
@@ -62,9 +71,16 @@ func (cfg ListenConfig) Listen(address string) (*Listener, error) {
 		cfg.StatusProvider = &DefaultStatusProvider{}
 	}
 
-	list := &Listener{address: address, authentication: !cfg.DisableAuthentication, errorLog: cfg.ErrorLog, listener: l, keyPair: key, verifyToken: token, incoming: make(chan *Connection)}
+	list := &Listener{address: address, authentication: !cfg.DisableAuthentication, errorLog: cfg.ErrorLog, listener: l, keyPair: key, verifyToken: token, incoming: make(chan *Connection), connections: make(map[*Connection]struct{})}
 	list.status.Store(cfg.StatusProvider)
 
+	if cfg.Query != nil {
+		if list.query, err = cfg.Query.Listen(l.Addr().String(), list); err != nil {
+			_ = l.Close()
+			return nil, err
+		}
+	}
+
 	go list.startListening()
 
 	return list, nil
@@ -78,6 +94,9 @@ func Listen(address string) (*Listener, error) {
 // Close closes the listener.
 func (l *Listener) Close() {
 	_ = l.listener.Close()
+	if l.query != nil {
+		_ = l.query.Close()
+	}
 	close(l.incoming)
 }
 
@@ -101,9 +120,45 @@ func (l *Listener) StatusProvider() StatusProvider {
 	return l.status.Load().(StatusProvider)
 }
 
-// Status returns the server status.
-func (l *Listener) Status() Status {
-	return l.StatusProvider().Status()
+// Status returns the server status for the request passed. The Listener of the request is set to the listener.
+func (l *Listener) Status(req StatusRequest) Status {
+	req.Listener = l
+	return l.StatusProvider().Status(req)
+}
+
+// Connections returns all connections that have logged in to the listener and are not yet closed.
+func (l *Listener) Connections() []*Connection {
+	l.connMu.Lock()
+	defer l.connMu.Unlock()
+	connections := make([]*Connection, 0, len(l.connections))
+	for conn := range l.connections {
+		connections = append(connections, conn)
+	}
+	return connections
+}
+
+// Players returns the players of all connections that have logged in to the listener and are not yet closed.
+func (l *Listener) Players() []Player {
+	connections := l.Connections()
+	players := make([]Player, 0, len(connections))
+	for _, conn := range connections {
+		players = append(players, Player{Name: conn.Name(), ID: conn.UUID()})
+	}
+	return players
+}
+
+// addConnection adds a connection that has logged in to the connections of the listener.
+func (l *Listener) addConnection(conn *Connection) {
+	l.connMu.Lock()
+	defer l.connMu.Unlock()
+	l.connections[conn] = struct{}{}
+}
+
+// removeConnection removes a closed connection from the connections of the listener.
+func (l *Listener) removeConnection(conn *Connection) {
+	l.connMu.Lock()
+	defer l.connMu.Unlock()
+	delete(l.connections, conn)
 }
 
 // startListening starts listening on the listener.
@@ -47,6 +47,20 @@ func (d *Decoder) Decode(v interface{}) error {
 	return d.unmarshalTag(val.Elem(), tagType, tagName)
 }
 
+// DecodeNamed reads the next NBT object from the input stream and stores it into the pointer to an object
+// passed, similarly to Decode, but also returns the name of the root tag.
+func (d *Decoder) DecodeNamed(v interface{}) (string, error) {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr {
+		return "", NonPointerTypeError{ActualType: val.Type()}
+	}
+	tagType, tagName, err := d.tag()
+	if err != nil {
+		return "", err
+	}
+	return tagName, d.unmarshalTag(val.Elem(), tagType, tagName)
+}
+
 // Unmarshal decodes a slice of NBT data into a pointer to a Go values passed. Marshal will use the
 // NetworkLittleEndian encoding by default. To use a specific encoding, use UnmarshalEncoding.
 //
@@ -106,6 +120,13 @@ var fieldMapPool = sync.Pool{
 // unmarshalTag decodes a tag from the decoder's input stream into the reflect.Value passed, assuming the tag
 // has the type and name passed.
 func (d *Decoder) unmarshalTag(val reflect.Value, tagType byte, tagName string) error {
+	if val.Kind() == reflect.Ptr {
+		// Pointers are decoded into the value they point to, which is allocated first if the pointer is nil.
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		val = val.Elem()
+	}
 	switch tagType {
 	default:
 		return UnknownTagError{Off: d.r.off, TagType: tagType, Op: "Match"}
@@ -7,7 +7,13 @@
 //
 // The package exposes serialisation and deserialisation roughly the same way as the JSON standard library
 // does, using nbt.Marshal() and nbt.Unmarshal when working with byte slices, and nbt.NewEncoder() and
-// nbt.NewDecoder() when working with readers or writers.
+// nbt.NewDecoder() when working with readers or writers. The stringified NBT (SNBT) used in commands and data
+// packs is supported through nbt.MarshalSNBT() and nbt.UnmarshalSNBT().
+//
+// Tools that must preserve the exact tags of NBT data, such as editors of vanilla world data, may decode the
+// data into a tag tree of nbt.Compound, nbt.List, nbt.Int and similar types using Decoder.DecodeTag(), which
+// Encoder.EncodeTag() writes back identically. Large data of which only a few tags are needed may be read
+// token by token using a TokenReader, which can skip entire compounds and lists without decoding them.
 //
 // The package encodes and decodes the following Go types with the following NBT tags.
 //   byte/uint8: TAG_Byte
@@ -24,11 +30,13 @@
 //   []<type>: TAG_List
 //   struct{...}: TAG_Compound
 //   map[string]<type/interface{}>: TAG_Compound
+//   *<type>: The tag of the type pointed to
 //
 // Structures decoded or encoded may have struct field tags in a comparable way to the JSON standard library.
 // The 'nbt' struct tag may be filled out the following ways:
 //   '-': Ignores the field completely when encoding and decoding.
-//   ',omitempty': Doesn't encode the field if its value is the same as the default value.
+//   ',omitempty': Doesn't encode the field if its value is the same as the default value. This may be used
+//                 with pointer fields for optional tags, which are then left out if the pointer is nil.
 //   'name(,omitempty)': Encodes/decodes the field with a different name than its usual name.
 // If no 'nbt' struct tag is present for a field, the name of the field will be used to encode/decode the
 // struct. Note that this package, unlike the JSON standard library package, is case sensitive when decoding.
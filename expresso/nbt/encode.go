@@ -51,6 +51,13 @@ func (e *Encoder) Encode(v interface{}) error {
 	return e.marshal(val, "")
 }
 
+// EncodeNamed encodes an object to NBT and writes it to the NBT output stream of the encoder, similarly to
+// Encode, but names the root tag with the name passed rather than leaving it empty.
+func (e *Encoder) EncodeNamed(v interface{}, name string) error {
+	val := reflect.ValueOf(v)
+	return e.marshal(val, name)
+}
+
 // Marshal encodes an object to its NBT representation and returns it as a byte slice. It uses the
 // NetworkLittleEndian NBT encoding. To use a specific encoding, use MarshalEncoding.
 //
@@ -110,6 +117,9 @@ func (e *Encoder) marshal(val reflect.Value, tagName string) error {
 		val = val.Elem()
 	}
 	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return IncompatibleTypeError{Type: val.Type(), ValueName: tagName}
+		}
 		val = val.Elem()
 	}
 	tagType := tagFromType(val.Type())
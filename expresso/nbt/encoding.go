@@ -37,7 +37,8 @@ var NetworkLittleEndian networkLittleEndian
 var LittleEndian littleEndian
 
 // BigEndian is the fixed size big endian implementation of NBT. It is the original implementation, and is
-// used only on Minecraft Java Edition.
+// used only on Minecraft Java Edition. Strings are encoded in the modified UTF-8 used by Java, unlike the other
+// encodings, which use regular UTF-8.
 var BigEndian bigEndian
 
 var _ = BigEndian
@@ -336,17 +337,17 @@ func (bigEndian) WriteFloat64(w *offsetWriter, x float64) error {
 	return nil
 }
 
-// WriteString ...
+// WriteString writes a string in the modified UTF-8 encoding used by Java.
 func (bigEndian) WriteString(w *offsetWriter, x string) error {
-	if len(x) > math.MaxInt16 {
+	data := encodeModifiedUTF8(x)
+	if len(data) > math.MaxUint16 {
 		return InvalidStringError{Off: w.off, String: x, Err: errors.New("string length exceeds maximum length prefix")}
 	}
-	length := int16(len(x))
+	length := uint16(len(data))
 	if _, err := w.Write([]byte{byte(length >> 8), byte(length)}); err != nil {
 		return FailedWriteError{Op: "WriteInt16", Off: w.off}
 	}
-	// Use unsafe conversion from a string to a byte slice to prevent copying.
-	if _, err := w.Write(*(*[]byte)(unsafe.Pointer(&x))); err != nil {
+	if _, err := w.Write(data); err != nil {
 		return FailedWriteError{Op: "WriteString", Off: w.off}
 	}
 	return nil
@@ -399,7 +400,7 @@ func (bigEndian) Float64(r *offsetReader) (float64, error) {
 		uint64(b[4])<<24 | uint64(b[5])<<16 | uint64(b[6])<<8 | uint64(b[7])), nil
 }
 
-// String ...
+// String reads a string in the modified UTF-8 encoding used by Java.
 func (bigEndian) String(r *offsetReader) (string, error) {
 	b, err := consumeN(2, r)
 	if err != nil {
@@ -410,7 +411,11 @@ func (bigEndian) String(r *offsetReader) (string, error) {
 	if err != nil {
 		return "", BufferOverrunError{Op: "String"}
 	}
-	return string(data), nil
+	s, err := decodeModifiedUTF8(data)
+	if err != nil {
+		return "", InvalidStringError{Off: r.off, Err: err}
+	}
+	return s, nil
 }
 
 // consumeN consumes n bytes from the offset reader and returns them. It returns an error if the reader does
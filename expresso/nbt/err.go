@@ -156,3 +156,14 @@ type MaximumBytesReadError struct {
 func (err MaximumBytesReadError) Error() string {
 	return fmt.Sprintf("nbt: limit of bytes read %v with NetworkLittleEndian format exhausted", maximumNetworkOffset)
 }
+
+// SyntaxError is returned when stringified NBT passed to UnmarshalSNBT is not valid.
+type SyntaxError struct {
+	Off     int64
+	Message string
+}
+
+// Error ...
+func (err SyntaxError) Error() string {
+	return fmt.Sprintf("nbt: invalid SNBT at offset %v: %v", err.Off, err.Message)
+}
@@ -0,0 +1,174 @@
+package nbt
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Compression is a compression algorithm that NBT data may be compressed with.
+type Compression byte
+
+const (
+	// CompressionNone is used for NBT data that is not compressed.
+	CompressionNone Compression = iota
+	// CompressionGzip is used for NBT data compressed with gzip, such as level.dat, player data and structure
+	// files.
+	CompressionGzip
+	// CompressionZlib is used for NBT data compressed with zlib, such as chunks in region files.
+	CompressionZlib
+)
+
+// String ...
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	case CompressionZlib:
+		return "zlib"
+	}
+	return fmt.Sprintf("Compression(%d)", byte(c))
+}
+
+// FileInfo holds the information about NBT data that is not part of its value: The name of the root tag and
+// the compression used. Data read using Read or ReadFile may be written back identically by passing the
+// FileInfo returned to Write or WriteFile.
+type FileInfo struct {
+	// Name is the name of the root tag. It is empty for most files.
+	Name string
+	// Compression is the compression of the data.
+	Compression Compression
+}
+
+// Read reads big endian NBT data from the reader passed into the pointer to a Go value passed. The data may be
+// compressed using gzip or zlib, or not be compressed at all, which is detected automatically. If a pointer to
+// a Tag is passed, the data is decoded as a tag tree.
+func Read(r io.Reader, v interface{}) (FileInfo, error) {
+	buf := bufio.NewReader(r)
+	info := FileInfo{Compression: detectCompression(buf)}
+
+	switch info.Compression {
+	case CompressionGzip:
+		gz, err := gzip.NewReader(buf)
+		if err != nil {
+			return info, err
+		}
+		defer gz.Close()
+		r = gz
+	case CompressionZlib:
+		zl, err := zlib.NewReader(buf)
+		if err != nil {
+			return info, err
+		}
+		defer zl.Close()
+		r = zl
+	default:
+		r = buf
+	}
+	dec := NewDecoder(r)
+	if t, ok := v.(*Tag); ok {
+		// Decode into a tag tree if a pointer to a Tag is passed, so that the exact tag types are kept.
+		var err error
+		info.Name, *t, err = dec.DecodeTag()
+		return info, err
+	}
+	name, err := dec.DecodeNamed(v)
+	info.Name = name
+	return info, err
+}
+
+// ReadFile reads the big endian NBT file at the path passed into the pointer to a Go value passed, similarly to
+// Read.
+func ReadFile(path string, v interface{}) (FileInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	defer f.Close()
+	return Read(f, v)
+}
+
+// Write writes the Go value passed as big endian NBT to the writer passed, with the root tag name and
+// compression of the FileInfo passed. Tag trees may be passed as well.
+func Write(w io.Writer, v interface{}, info FileInfo) error {
+	var wc io.WriteCloser
+	switch info.Compression {
+	case CompressionNone:
+		return encodeFile(NewEncoder(w), v, info.Name)
+	case CompressionGzip:
+		wc = gzip.NewWriter(w)
+	case CompressionZlib:
+		wc = zlib.NewWriter(w)
+	default:
+		return fmt.Errorf("nbt: unknown compression %v", info.Compression)
+	}
+	if err := encodeFile(NewEncoder(wc), v, info.Name); err != nil {
+		return err
+	}
+	return wc.Close()
+}
+
+// encodeFile encodes the value passed with the root name passed using the encoder passed. Tag trees are
+// encoded as such, and any other value is encoded using reflection.
+func encodeFile(enc *Encoder, v interface{}, name string) error {
+	if t, ok := v.(Tag); ok {
+		return enc.EncodeTag(name, t)
+	}
+	return enc.EncodeNamed(v, name)
+}
+
+// WriteFile writes the Go value passed as big endian NBT to the file at the path passed, similarly to Write.
+// The data is first written to a temporary file which then replaces the file, so that the file is never left
+// partially written. The permissions of an existing file are kept, while new files are created with mode 0644.
+func WriteFile(path string, v interface{}, info FileInfo) error {
+	mode := os.FileMode(0644)
+	if stat, err := os.Stat(path); err == nil {
+		mode = stat.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	w := bufio.NewWriter(f)
+	if err := Write(w, v, info); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// Temporary files are created with mode 0600.
+	if err := os.Chmod(f.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// detectCompression detects the compression of the data in the reader passed by peeking at its first bytes.
+func detectCompression(r *bufio.Reader) Compression {
+	header, err := r.Peek(2)
+	if err != nil {
+		return CompressionNone
+	}
+	switch {
+	case header[0] == 0x1f && header[1] == 0x8b:
+		return CompressionGzip
+	case header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0:
+		// A zlib header with the deflate method and a valid check value. Uncompressed NBT always starts with
+		// a tag type, which is never 8 in its lower bits for compounds.
+		return CompressionZlib
+	}
+	return CompressionNone
+}
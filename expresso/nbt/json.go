@@ -0,0 +1,307 @@
+package nbt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// MarshalJSON encodes a tag tree with the root name passed to a JSON representation that keeps the exact type
+// of every tag, so that UnmarshalJSON produces an identical tag tree. Every tag is an object holding its type
+// and value, such as {"type":"int","value":3}, and the root holds its name as well. Compound values are objects
+// of tags in the order of the compound, list values are objects holding the element type and an array of the
+// element values, and the values of all other tags are numbers, strings or arrays of numbers. Floating point
+// numbers that JSON cannot represent are written as the strings "NaN", "Infinity" and "-Infinity".
+func MarshalJSON(name string, t Tag) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(`{"name":`)
+	writeJSONString(buf, name)
+	buf.WriteByte(',')
+	if err := writeJSONTagFields(buf, t); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes the JSON representation of a tag tree produced by MarshalJSON, and returns the tag tree
+// along with its root name.
+func UnmarshalJSON(data []byte) (string, Tag, error) {
+	var root struct {
+		Name  string          `json:"name"`
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return "", nil, err
+	}
+	t, err := readJSONTag(root.Type, root.Value)
+	return root.Name, t, err
+}
+
+// jsonTypeNames holds the names of tag types in the JSON representation.
+var jsonTypeNames = map[TagType]string{
+	TagEnd:       "end",
+	TagByte:      "byte",
+	TagShort:     "short",
+	TagInt:       "int",
+	TagLong:      "long",
+	TagFloat:     "float",
+	TagDouble:    "double",
+	TagByteArray: "byte_array",
+	TagString:    "string",
+	TagList:      "list",
+	TagCompound:  "compound",
+	TagIntArray:  "int_array",
+	TagLongArray: "long_array",
+}
+
+// jsonTypeFromName returns the tag type with the JSON name passed.
+func jsonTypeFromName(name string) (TagType, error) {
+	for t, n := range jsonTypeNames {
+		if n == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("nbt: unknown tag type %q in JSON", name)
+}
+
+// writeJSONTagFields writes the type and value fields of the tag passed, without the braces around them.
+func writeJSONTagFields(buf *bytes.Buffer, t Tag) error {
+	buf.WriteString(`"type":"` + jsonTypeNames[t.Type()] + `","value":`)
+	return writeJSONValue(buf, t)
+}
+
+// writeJSONValue writes the value of the tag passed.
+func writeJSONValue(buf *bytes.Buffer, t Tag) error {
+	switch v := t.(type) {
+	case Byte:
+		buf.WriteString(strconv.Itoa(int(int8(v))))
+	case Short:
+		buf.WriteString(strconv.Itoa(int(v)))
+	case Int:
+		buf.WriteString(strconv.Itoa(int(v)))
+	case Long:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case Float:
+		writeJSONFloat(buf, float64(v), 32)
+	case Double:
+		writeJSONFloat(buf, float64(v), 64)
+	case String:
+		writeJSONString(buf, string(v))
+	case ByteArray:
+		buf.WriteByte('[')
+		for i, n := range v {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.Itoa(int(int8(n))))
+		}
+		buf.WriteByte(']')
+	case IntArray:
+		buf.WriteByte('[')
+		for i, n := range v {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.Itoa(int(n)))
+		}
+		buf.WriteByte(']')
+	case LongArray:
+		buf.WriteByte('[')
+		for i, n := range v {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.FormatInt(n, 10))
+		}
+		buf.WriteByte(']')
+	case *List:
+		buf.WriteString(`{"elem":"` + jsonTypeNames[v.ElemType] + `","values":[`)
+		for i, nested := range v.Tags {
+			if nested.Type() != v.ElemType {
+				return fmt.Errorf("nbt: list of %v holds %v", v.ElemType, nested.Type())
+			}
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONValue(buf, nested); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]}")
+	case *Compound:
+		buf.WriteByte('{')
+		for i, key := range v.keys {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, key)
+			buf.WriteString(":{")
+			if err := writeJSONTagFields(buf, v.values[key]); err != nil {
+				return err
+			}
+			buf.WriteByte('}')
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("nbt: unknown tag %T", t)
+	}
+	return nil
+}
+
+// writeJSONFloat writes a floating point number with the bit size passed. Numbers that cannot be represented
+// in JSON are written as a string.
+func writeJSONFloat(buf *bytes.Buffer, f float64, bitSize int) {
+	switch {
+	case math.IsNaN(f):
+		buf.WriteString(`"NaN"`)
+	case math.IsInf(f, 1):
+		buf.WriteString(`"Infinity"`)
+	case math.IsInf(f, -1):
+		buf.WriteString(`"-Infinity"`)
+	default:
+		buf.WriteString(strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+}
+
+// writeJSONString writes a string as a JSON string.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	data, _ := json.Marshal(s)
+	buf.Write(data)
+}
+
+// readJSONTag reads the value of a tag with the JSON type name passed.
+func readJSONTag(typeName string, value json.RawMessage) (Tag, error) {
+	tagType, err := jsonTypeFromName(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return readJSONValue(tagType, value)
+}
+
+// readJSONValue reads the JSON value of a tag with the type passed.
+func readJSONValue(tagType TagType, value json.RawMessage) (Tag, error) {
+	switch tagType {
+	case TagByte:
+		n, err := readJSONInt(value, 8)
+		return Byte(int8(n)), err
+	case TagShort:
+		n, err := readJSONInt(value, 16)
+		return Short(n), err
+	case TagInt:
+		n, err := readJSONInt(value, 32)
+		return Int(n), err
+	case TagLong:
+		n, err := readJSONInt(value, 64)
+		return Long(n), err
+	case TagFloat:
+		f, err := readJSONFloat(value, 32)
+		return Float(f), err
+	case TagDouble:
+		f, err := readJSONFloat(value, 64)
+		return Double(f), err
+	case TagString:
+		var s string
+		err := json.Unmarshal(value, &s)
+		return String(s), err
+	case TagByteArray:
+		var values []int8
+		if err := json.Unmarshal(value, &values); err != nil {
+			return nil, err
+		}
+		arr := make(ByteArray, len(values))
+		for i, n := range values {
+			arr[i] = byte(n)
+		}
+		return arr, nil
+	case TagIntArray:
+		var arr IntArray
+		err := json.Unmarshal(value, &arr)
+		return arr, err
+	case TagLongArray:
+		var arr LongArray
+		err := json.Unmarshal(value, &arr)
+		return arr, err
+	case TagList:
+		var list struct {
+			Elem   string            `json:"elem"`
+			Values []json.RawMessage `json:"values"`
+		}
+		if err := json.Unmarshal(value, &list); err != nil {
+			return nil, err
+		}
+		elemType, err := jsonTypeFromName(list.Elem)
+		if err != nil {
+			return nil, err
+		}
+		l := &List{ElemType: elemType, Tags: make([]Tag, 0, len(list.Values))}
+		for _, v := range list.Values {
+			nested, err := readJSONValue(elemType, v)
+			if err != nil {
+				return nil, err
+			}
+			l.Tags = append(l.Tags, nested)
+		}
+		return l, nil
+	case TagCompound:
+		return readJSONCompound(value)
+	}
+	return nil, fmt.Errorf("nbt: unexpected %v in JSON", tagType)
+}
+
+// readJSONCompound reads the JSON value of a compound, keeping the order of its entries.
+func readJSONCompound(value json.RawMessage) (Tag, error) {
+	dec := json.NewDecoder(bytes.NewReader(value))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("nbt: expected object for compound in JSON, got %v", tok)
+	}
+	c := NewCompound()
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+		var entry struct {
+			Type  string          `json:"type"`
+			Value json.RawMessage `json:"value"`
+		}
+		if err := dec.Decode(&entry); err != nil {
+			return nil, err
+		}
+		t, err := readJSONTag(entry.Type, entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", key, err)
+		}
+		c.Set(key, t)
+	}
+	return c, nil
+}
+
+// readJSONInt reads a JSON number as an integer with the bit size passed.
+func readJSONInt(value json.RawMessage, bitSize int) (int64, error) {
+	return strconv.ParseInt(string(bytes.TrimSpace(value)), 10, bitSize)
+}
+
+// readJSONFloat reads a JSON number, or one of the strings written for numbers that JSON cannot represent, as
+// a floating point number with the bit size passed.
+func readJSONFloat(value json.RawMessage, bitSize int) (float64, error) {
+	var s string
+	if json.Unmarshal(value, &s) == nil {
+		switch s {
+		case "NaN":
+			return math.NaN(), nil
+		case "Infinity":
+			return math.Inf(1), nil
+		case "-Infinity":
+			return math.Inf(-1), nil
+		}
+		return 0, fmt.Errorf("nbt: invalid floating point number %q in JSON", s)
+	}
+	return strconv.ParseFloat(string(bytes.TrimSpace(value)), bitSize)
+}
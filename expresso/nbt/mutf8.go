@@ -0,0 +1,87 @@
+package nbt
+
+import (
+	"errors"
+	"unicode/utf16"
+)
+
+// encodeModifiedUTF8 encodes a string to the modified UTF-8 used by Java. It differs from regular UTF-8 in
+// that the NUL character is encoded as two bytes, and characters outside the Basic Multilingual Plane are
+// encoded as a UTF-16 surrogate pair, with each surrogate encoded as three bytes.
+func encodeModifiedUTF8(s string) []byte {
+	if isPlainASCII(s) {
+		return []byte(s)
+	}
+	b := make([]byte, 0, len(s)+len(s)/2)
+	for _, r := range s {
+		switch {
+		case r == 0:
+			b = append(b, 0xc0, 0x80)
+		case r < 0x80:
+			b = append(b, byte(r))
+		case r < 0x800:
+			b = append(b, 0xc0|byte(r>>6), 0x80|byte(r&0x3f))
+		case r < 0x10000:
+			b = appendModifiedUTF8Char(b, r)
+		default:
+			high, low := utf16.EncodeRune(r)
+			b = appendModifiedUTF8Char(appendModifiedUTF8Char(b, high), low)
+		}
+	}
+	return b
+}
+
+// appendModifiedUTF8Char appends a character of the Basic Multilingual Plane that takes three bytes in
+// modified UTF-8 to the byte slice passed.
+func appendModifiedUTF8Char(b []byte, r rune) []byte {
+	return append(b, 0xe0|byte(r>>12), 0x80|byte((r>>6)&0x3f), 0x80|byte(r&0x3f))
+}
+
+// decodeModifiedUTF8 decodes a string encoded in the modified UTF-8 used by Java to a regular UTF-8 string.
+// Unpaired surrogates are replaced with the Unicode replacement character.
+func decodeModifiedUTF8(b []byte) (string, error) {
+	if isPlainASCII(string(b)) {
+		return string(b), nil
+	}
+	runes := make([]rune, 0, len(b))
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c < 0x80:
+			runes = append(runes, rune(c))
+			i++
+		case c&0xe0 == 0xc0:
+			if i+1 >= len(b) || b[i+1]&0xc0 != 0x80 {
+				return "", errors.New("malformed two byte modified utf8 character")
+			}
+			runes = append(runes, rune(c&0x1f)<<6|rune(b[i+1]&0x3f))
+			i += 2
+		case c&0xf0 == 0xe0:
+			if i+2 >= len(b) || b[i+1]&0xc0 != 0x80 || b[i+2]&0xc0 != 0x80 {
+				return "", errors.New("malformed three byte modified utf8 character")
+			}
+			runes = append(runes, rune(c&0x0f)<<12|rune(b[i+1]&0x3f)<<6|rune(b[i+2]&0x3f))
+			i += 3
+		default:
+			return "", errors.New("invalid modified utf8 byte")
+		}
+	}
+	// utf16.Decode combines surrogate pairs and replaces unpaired surrogates with the replacement
+	// character.
+	units := make([]uint16, len(runes))
+	for i, r := range runes {
+		units[i] = uint16(r)
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// isPlainASCII checks if a string consists only of ASCII characters other than NUL, which are encoded the same
+// in UTF-8 and modified UTF-8.
+func isPlainASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0 || s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
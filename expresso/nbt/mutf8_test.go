@@ -0,0 +1,112 @@
+package nbt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// TestModifiedUTF8 tests the encoding of strings to modified UTF-8 and back.
+func TestModifiedUTF8(t *testing.T) {
+	for _, test := range []struct {
+		s        string
+		expected []byte
+	}{
+		{"stone", []byte("stone")},
+		{"", []byte{}},
+		{"\x00", []byte{0xc0, 0x80}},
+		{"a\x00b", []byte{'a', 0xc0, 0x80, 'b'}},
+		{"é", []byte{0xc3, 0xa9}},
+		{"§", []byte{0xc2, 0xa7}},
+		{"€", []byte{0xe2, 0x82, 0xac}},
+		// U+1F600 is encoded as the surrogate pair U+D83D U+DE00, each as three bytes.
+		{"😀", []byte{0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80}},
+	} {
+		b := encodeModifiedUTF8(test.s)
+		if !bytes.Equal(b, test.expected) {
+			t.Errorf("%q: expected %x, got %x", test.s, test.expected, b)
+		}
+		s, err := decodeModifiedUTF8(b)
+		if err != nil {
+			t.Errorf("%q: decode: %v", test.s, err)
+			continue
+		}
+		if s != test.s {
+			t.Errorf("%q: decoded to %q", test.s, s)
+		}
+	}
+
+	// Unpaired surrogates are replaced, and malformed sequences are rejected.
+	if s, err := decodeModifiedUTF8([]byte{0xed, 0xa0, 0xbd, 'a'}); err != nil || s != "�a" {
+		t.Errorf("unpaired surrogate: got %q, %v", s, err)
+	}
+	for _, b := range [][]byte{{0xc0}, {0xe0, 0x80}, {0xc0, 'a'}, {0xf0, 0x9f, 0x98, 0x80}, {0x80}} {
+		if s, err := decodeModifiedUTF8(b); err == nil {
+			t.Errorf("%x: expected error, got %q", b, s)
+		}
+	}
+}
+
+// TestModifiedUTF8Compound tests that strings holding NUL characters and characters outside the Basic
+// Multilingual Plane survive a round trip in the BigEndian encoding, while the Bedrock encodings keep writing
+// regular UTF-8.
+func TestModifiedUTF8Compound(t *testing.T) {
+	s := "a\x00😀"
+	sign := compoundOf(
+		"id", String("minecraft:sign"),
+		"Text1", String(`{"text":"`+s+`"}`),
+		"Item", compoundOf("id", String("minecraft:paper"), "Count", Byte(1), "tag", compoundOf(
+			"display", compoundOf("Name", String(s)),
+		)),
+	)
+
+	buf := &bytes.Buffer{}
+	if err := NewEncoderWithEncoding(buf, BigEndian).EncodeTag("", sign); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("😀")) {
+		t.Errorf("BigEndian data holds regular UTF-8: %x", buf.Bytes())
+	}
+	if !bytes.Contains(buf.Bytes(), []byte{'a', 0xc0, 0x80, 0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80}) {
+		t.Errorf("BigEndian data does not hold modified UTF-8: %x", buf.Bytes())
+	}
+	_, decoded, err := NewDecoderWithEncoding(bytes.NewReader(buf.Bytes()), BigEndian).DecodeTag()
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, sign) {
+		t.Errorf("expected %#v, got %#v", sign, decoded)
+	}
+
+	var m map[string]interface{}
+	if err := UnmarshalEncoding(buf.Bytes(), &m, BigEndian); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if name := m["Item"].(map[string]interface{})["tag"].(map[string]interface{})["display"].(map[string]interface{})["Name"]; name != s {
+		t.Errorf("expected name %q, got %q", s, name)
+	}
+
+	for _, test := range []struct {
+		name     string
+		encoding Encoding
+		expected []byte
+	}{
+		{"LittleEndian", LittleEndian, append([]byte{byte(len(s)), 0}, s...)},
+		{"NetworkLittleEndian", NetworkLittleEndian, append([]byte{byte(len(s))}, s...)},
+	} {
+		buf := &bytes.Buffer{}
+		if err := NewEncoderWithEncoding(buf, test.encoding).EncodeTag("", compoundOf("Name", String(s))); err != nil {
+			t.Fatalf("%v: encode: %v", test.name, err)
+		}
+		if !bytes.Contains(buf.Bytes(), test.expected) {
+			t.Errorf("%v: expected regular UTF-8 string %x in %x", test.name, test.expected, buf.Bytes())
+		}
+		_, decoded, err := NewDecoderWithEncoding(bytes.NewReader(buf.Bytes()), test.encoding).DecodeTag()
+		if err != nil {
+			t.Fatalf("%v: decode: %v", test.name, err)
+		}
+		if name, _ := decoded.(*Compound).Tag("Name"); name != String(s) {
+			t.Errorf("%v: expected name %q, got %q", test.name, s, name)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package nbt
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MarshalSNBT encodes an object to its stringified NBT (SNBT) representation, as used in commands and data
+// packs, such as {Count:1b,id:"minecraft:stone"}. The Go value passed may be any value that Marshal accepts,
+// or a Tag. Keys of compound tags are sorted, so that the output is the same every time, except for tag trees,
+// of which the order of compound entries is kept.
+func MarshalSNBT(v interface{}) (string, error) {
+	t, ok := v.(Tag)
+	if !ok {
+		data, err := MarshalEncoding(v, BigEndian)
+		if err != nil {
+			return "", err
+		}
+		if _, t, err = NewDecoderWithEncoding(bytes.NewReader(data), BigEndian).DecodeTag(); err != nil {
+			return "", err
+		}
+	}
+	b := &strings.Builder{}
+	writeSNBT(b, t, !ok)
+	return b.String(), nil
+}
+
+// UnmarshalSNBT decodes stringified NBT (SNBT) into a pointer to a Go value passed. The value passed may be
+// of any type that Unmarshal accepts, and the same conversions between tags and Go types apply. If a pointer
+// to a Tag is passed, the SNBT is decoded into a tag tree.
+//
+// Numbers are typed by their suffix: b for TAG_Byte, s for TAG_Short, l for TAG_Long, f for TAG_Float and d
+// for TAG_Double. Integers without a suffix are TAG_Int and decimals without a suffix are TAG_Double. The
+// literals true and false are TAG_Byte. Typed arrays are written as [B;...], [I;...] and [L;...].
+func UnmarshalSNBT(data []byte, v interface{}) error {
+	p := &snbtParser{s: string(data)}
+	t, err := p.value()
+	if err != nil {
+		return err
+	}
+	p.skipWhitespace()
+	if p.off != len(p.s) {
+		return p.errorf("unexpected trailing data")
+	}
+	if ptr, ok := v.(*Tag); ok {
+		*ptr = t
+		return nil
+	}
+	buf := &bytes.Buffer{}
+	if err := NewEncoderWithEncoding(buf, BigEndian).EncodeTag("", t); err != nil {
+		return err
+	}
+	return UnmarshalEncoding(buf.Bytes(), v, BigEndian)
+}
+
+// writeSNBT writes the SNBT representation of a tag to the builder passed. If sortKeys is true, the entries of
+// compounds are written sorted by their keys.
+func writeSNBT(b *strings.Builder, t Tag, sortKeys bool) {
+	switch v := t.(type) {
+	case Byte:
+		b.WriteString(strconv.Itoa(int(int8(v))) + "b")
+	case Short:
+		b.WriteString(strconv.Itoa(int(v)) + "s")
+	case Int:
+		b.WriteString(strconv.Itoa(int(v)))
+	case Long:
+		b.WriteString(strconv.FormatInt(int64(v), 10) + "L")
+	case Float:
+		b.WriteString(formatSNBTFloat(float64(v), 32) + "f")
+	case Double:
+		b.WriteString(formatSNBTFloat(float64(v), 64) + "d")
+	case String:
+		b.WriteString(quoteSNBT(string(v)))
+	case *Compound:
+		keys := v.keys
+		if sortKeys {
+			keys = v.Keys()
+			sort.Strings(keys)
+		}
+		b.WriteByte('{')
+		for i, k := range keys {
+			if i != 0 {
+				b.WriteByte(',')
+			}
+			if snbtUnquotedPattern.MatchString(k) {
+				b.WriteString(k)
+			} else {
+				b.WriteString(quoteSNBT(k))
+			}
+			b.WriteByte(':')
+			writeSNBT(b, v.values[k], sortKeys)
+		}
+		b.WriteByte('}')
+	case *List:
+		b.WriteByte('[')
+		for i, nested := range v.Tags {
+			if i != 0 {
+				b.WriteByte(',')
+			}
+			writeSNBT(b, nested, sortKeys)
+		}
+		b.WriteByte(']')
+	case ByteArray:
+		b.WriteString("[B;")
+		for i, n := range v {
+			if i != 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Itoa(int(int8(n))) + "B")
+		}
+		b.WriteByte(']')
+	case IntArray:
+		b.WriteString("[I;")
+		for i, n := range v {
+			if i != 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Itoa(int(n)))
+		}
+		b.WriteByte(']')
+	case LongArray:
+		b.WriteString("[L;")
+		for i, n := range v {
+			if i != 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.FormatInt(n, 10) + "L")
+		}
+		b.WriteByte(']')
+	}
+}
+
+// formatSNBTFloat formats a floating point number with the bit size passed so that it always contains a
+// decimal point or exponent, and thus reads back as a floating point number.
+func formatSNBTFloat(f float64, bitSize int) string {
+	s := strconv.FormatFloat(f, 'g', -1, bitSize)
+	if !strings.ContainsAny(s, ".eIN") {
+		s += ".0"
+	}
+	return s
+}
+
+// quoteSNBT quotes a string for use in SNBT. Double quotes are used, unless the string contains double quotes
+// but no single quotes, in which case single quotes are used to avoid escaping.
+func quoteSNBT(s string) string {
+	quote := byte('"')
+	if strings.IndexByte(s, '"') != -1 && strings.IndexByte(s, '\'') == -1 {
+		quote = '\''
+	}
+	b := strings.Builder{}
+	b.Grow(len(s) + 2)
+	b.WriteByte(quote)
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' || s[i] == quote {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte(quote)
+	return b.String()
+}
+
+var (
+	// snbtUnquotedPattern matches strings that may be written in SNBT without quotes.
+	snbtUnquotedPattern = regexp.MustCompile(`^[0-9A-Za-z_\-.+]+$`)
+
+	snbtBytePattern   = regexp.MustCompile(`^[-+]?(?:0|[1-9][0-9]*)[bB]$`)
+	snbtShortPattern  = regexp.MustCompile(`^[-+]?(?:0|[1-9][0-9]*)[sS]$`)
+	snbtIntPattern    = regexp.MustCompile(`^[-+]?(?:0|[1-9][0-9]*)$`)
+	snbtLongPattern   = regexp.MustCompile(`^[-+]?(?:0|[1-9][0-9]*)[lL]$`)
+	snbtFloatPattern  = regexp.MustCompile(`^[-+]?(?:[0-9]+[.]?|[0-9]*[.][0-9]+)(?:[eE][-+]?[0-9]+)?[fF]$`)
+	snbtDoublePattern = regexp.MustCompile(`^[-+]?(?:[0-9]+[.]?|[0-9]*[.][0-9]+)(?:[eE][-+]?[0-9]+)?[dD]$`)
+	// snbtDecimalPattern matches doubles without a suffix, which must have a decimal point.
+	snbtDecimalPattern = regexp.MustCompile(`^[-+]?(?:[0-9]+[.]|[0-9]*[.][0-9]+)(?:[eE][-+]?[0-9]+)?$`)
+)
+
+// snbtParser parses stringified NBT into a tag tree.
+type snbtParser struct {
+	s   string
+	off int
+}
+
+// value parses any SNBT value at the current offset.
+func (p *snbtParser) value() (Tag, error) {
+	p.skipWhitespace()
+	if p.off >= len(p.s) {
+		return nil, p.errorf("expected value")
+	}
+	switch p.s[p.off] {
+	case '{':
+		return p.compound()
+	case '[':
+		if p.off+2 < len(p.s) && p.s[p.off+2] == ';' {
+			return p.array()
+		}
+		return p.list()
+	case '"', '\'':
+		s, err := p.quoted()
+		return String(s), err
+	}
+	s := p.unquoted()
+	if s == "" {
+		return nil, p.errorf("expected value")
+	}
+	return typedSNBT(s), nil
+}
+
+// compound parses a compound tag of the form {key:value,...}.
+func (p *snbtParser) compound() (Tag, error) {
+	p.off++
+	c := NewCompound()
+	p.skipWhitespace()
+	if p.consume('}') {
+		return c, nil
+	}
+	for {
+		p.skipWhitespace()
+		var key string
+		if p.off < len(p.s) && (p.s[p.off] == '"' || p.s[p.off] == '\'') {
+			var err error
+			if key, err = p.quoted(); err != nil {
+				return nil, err
+			}
+		} else if key = p.unquoted(); key == "" {
+			return nil, p.errorf("expected key")
+		}
+		p.skipWhitespace()
+		if !p.consume(':') {
+			return nil, p.errorf("expected ':' after key %q", key)
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		c.Set(key, v)
+
+		p.skipWhitespace()
+		if p.consume('}') {
+			return c, nil
+		}
+		if !p.consume(',') {
+			return nil, p.errorf("expected ',' or '}'")
+		}
+	}
+}
+
+// list parses a list tag of the form [value,...]. All values must have the same tag type.
+func (p *snbtParser) list() (Tag, error) {
+	p.off++
+	l := NewList(TagEnd)
+	p.skipWhitespace()
+	if p.consume(']') {
+		return l, nil
+	}
+	for {
+		start := p.off
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if err := l.Add(v); err != nil {
+			p.off = start
+			return nil, p.errorf("list cannot contain both %v and %v", l.ElemType, v.Type())
+		}
+
+		p.skipWhitespace()
+		if p.consume(']') {
+			return l, nil
+		}
+		if !p.consume(',') {
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+// array parses a typed array of the form [B;...], [I;...] or [L;...].
+func (p *snbtParser) array() (Tag, error) {
+	arrayType := p.s[p.off+1]
+	if arrayType != 'B' && arrayType != 'I' && arrayType != 'L' {
+		p.off++
+		return nil, p.errorf("invalid array type %q", arrayType)
+	}
+	p.off += 3
+
+	var values []int64
+	p.skipWhitespace()
+	if !p.consume(']') {
+		for {
+			start := p.off
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			// Like in vanilla, byte arrays may only hold bytes, int arrays may also hold bytes and shorts, and
+			// long arrays may hold any integer.
+			var n int64
+			switch v := v.(type) {
+			case Byte:
+				n = int64(int8(v))
+			case Short:
+				n = int64(v)
+			case Int:
+				n = int64(v)
+			case Long:
+				n = int64(v)
+			}
+			if !arrayAccepts(arrayType, v.Type()) {
+				p.off = start
+				return nil, p.errorf("invalid %v in array of type %c", v.Type(), arrayType)
+			}
+			values = append(values, n)
+
+			p.skipWhitespace()
+			if p.consume(']') {
+				break
+			}
+			if !p.consume(',') {
+				return nil, p.errorf("expected ',' or ']'")
+			}
+		}
+	}
+	switch arrayType {
+	case 'B':
+		arr := make(ByteArray, len(values))
+		for i, n := range values {
+			arr[i] = byte(n)
+		}
+		return arr, nil
+	case 'I':
+		arr := make(IntArray, len(values))
+		for i, n := range values {
+			arr[i] = int32(n)
+		}
+		return arr, nil
+	}
+	return LongArray(values), nil
+}
+
+// arrayAccepts checks if an array of the type passed, B, I or L, may hold a value with the tag type passed.
+func arrayAccepts(arrayType byte, t TagType) bool {
+	switch t {
+	case TagByte:
+		return true
+	case TagShort:
+		return arrayType != 'B'
+	case TagInt:
+		return arrayType != 'B'
+	case TagLong:
+		return arrayType == 'L'
+	}
+	return false
+}
+
+// quoted parses a string enclosed by single or double quotes. Backslashes escape the next character.
+func (p *snbtParser) quoted() (string, error) {
+	quote := p.s[p.off]
+	p.off++
+	b := strings.Builder{}
+	for p.off < len(p.s) {
+		c := p.s[p.off]
+		p.off++
+		switch c {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.off >= len(p.s) {
+				return "", p.errorf("unterminated string")
+			}
+			if next := p.s[p.off]; next != '\\' && next != '"' && next != '\'' {
+				return "", p.errorf("invalid escape sequence \\%c", next)
+			}
+			b.WriteByte(p.s[p.off])
+			p.off++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// unquoted reads an unquoted string of the characters allowed in SNBT without quotes.
+func (p *snbtParser) unquoted() string {
+	start := p.off
+	for p.off < len(p.s) {
+		c := p.s[p.off]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '-' || c == '.' || c == '+') {
+			break
+		}
+		p.off++
+	}
+	return p.s[start:p.off]
+}
+
+// typedSNBT converts an unquoted string to the tag it represents. Strings that are not a valid number, or are
+// numbers out of the range of their type, are kept as a string.
+func typedSNBT(s string) Tag {
+	switch {
+	case snbtBytePattern.MatchString(s):
+		if n, err := strconv.ParseInt(s[:len(s)-1], 10, 8); err == nil {
+			return Byte(int8(n))
+		}
+	case snbtShortPattern.MatchString(s):
+		if n, err := strconv.ParseInt(s[:len(s)-1], 10, 16); err == nil {
+			return Short(n)
+		}
+	case snbtIntPattern.MatchString(s):
+		if n, err := strconv.ParseInt(s, 10, 32); err == nil {
+			return Int(n)
+		}
+	case snbtLongPattern.MatchString(s):
+		if n, err := strconv.ParseInt(s[:len(s)-1], 10, 64); err == nil {
+			return Long(n)
+		}
+	case snbtFloatPattern.MatchString(s):
+		if f, err := strconv.ParseFloat(s[:len(s)-1], 32); err == nil && !math.IsInf(f, 0) {
+			return Float(f)
+		}
+	case snbtDoublePattern.MatchString(s):
+		if f, err := strconv.ParseFloat(s[:len(s)-1], 64); err == nil && !math.IsInf(f, 0) {
+			return Double(f)
+		}
+	case snbtDecimalPattern.MatchString(s):
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) {
+			return Double(f)
+		}
+	case s == "true":
+		return Byte(1)
+	case s == "false":
+		return Byte(0)
+	}
+	return String(s)
+}
+
+// skipWhitespace skips any whitespace at the current offset.
+func (p *snbtParser) skipWhitespace() {
+	for p.off < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.off]) != -1 {
+		p.off++
+	}
+}
+
+// consume skips the byte passed if it is the byte at the current offset. True is returned if it was.
+func (p *snbtParser) consume(c byte) bool {
+	if p.off < len(p.s) && p.s[p.off] == c {
+		p.off++
+		return true
+	}
+	return false
+}
+
+// errorf returns a SyntaxError at the current offset with the message passed.
+func (p *snbtParser) errorf(format string, a ...interface{}) error {
+	return SyntaxError{Off: int64(p.off), Message: fmt.Sprintf(format, a...)}
+}
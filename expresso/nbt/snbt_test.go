@@ -0,0 +1,201 @@
+package nbt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// TestUnmarshalSNBTTypes tests that numbers in SNBT are decoded to the tag type of their suffix, and that
+// typed arrays are decoded to arrays of the right type.
+func TestUnmarshalSNBTTypes(t *testing.T) {
+	for s, expected := range map[string]Tag{
+		"1b":                 Byte(1),
+		"-128B":              Byte(0x80),
+		"true":               Byte(1),
+		"false":              Byte(0),
+		"300s":               Short(300),
+		"-5S":                Short(-5),
+		"70000":              Int(70000),
+		"9000000000L":        Long(9000000000),
+		"-1l":                Long(-1),
+		"1.5f":               Float(1.5),
+		"2F":                 Float(2),
+		"2.25d":              Double(2.25),
+		"3D":                 Double(3),
+		"0.5":                Double(0.5),
+		"128b":               String("128b"),
+		"1.2.3":              String("1.2.3"),
+		"minecraft:stone":    nil,
+		"[B;1b,-2b]":         ByteArray{1, 0xfe},
+		"[B;]":               ByteArray{},
+		"[I;1,2s,3b]":        IntArray{1, 2, 3},
+		"[L;1L,2,-3s,4b]":    LongArray{1, 2, -3, 4},
+		"[ I ; 1 ]":          nil,
+		"[I;1L]":             nil,
+		"[B;1]":              nil,
+		"[X;1]":              nil,
+		"[1b,2b]":            &List{ElemType: TagByte, Tags: []Tag{Byte(1), Byte(2)}},
+		"[1b,2s]":            nil,
+		"{a:1b,b:[I;5]}":     compoundOf("a", Byte(1), "b", IntArray{5}),
+		"{ \"a b\" : 1s }":   compoundOf("a b", Short(1)),
+		"{a:1b,}":            nil,
+		"{a:1b} trailing":    nil,
+		"[{a:1b},{b:2s},{}]": &List{ElemType: TagCompound, Tags: []Tag{compoundOf("a", Byte(1)), compoundOf("b", Short(2)), NewCompound()}},
+	} {
+		var tag Tag
+		err := UnmarshalSNBT([]byte(s), &tag)
+		if expected == nil {
+			if err == nil {
+				t.Errorf("%v: expected error, got %#v", s, tag)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(tag, expected) {
+			t.Errorf("%v: expected %#v, got %#v", s, expected, tag)
+		}
+	}
+}
+
+// TestMarshalSNBTTypes tests that tags are encoded to SNBT with the suffix of their type.
+func TestMarshalSNBTTypes(t *testing.T) {
+	for _, test := range []struct {
+		tag      Tag
+		expected string
+	}{
+		{Byte(0xff), "-1b"},
+		{Short(-300), "-300s"},
+		{Int(7), "7"},
+		{Long(9000000000), "9000000000L"},
+		{Float(1.5), "1.5f"},
+		{Float(2), "2.0f"},
+		{Double(3), "3.0d"},
+		{Double(1e300), "1e+300d"},
+		{ByteArray{1, 0xfe}, "[B;1B,-2B]"},
+		{IntArray{1, -2}, "[I;1,-2]"},
+		{LongArray{1, -2}, "[L;1L,-2L]"},
+		{IntArray{}, "[I;]"},
+		{&List{ElemType: TagShort, Tags: []Tag{Short(1), Short(2)}}, "[1s,2s]"},
+		{compoundOf("b", Int(1), "a", Int(2)), "{b:1,a:2}"},
+	} {
+		s, err := MarshalSNBT(test.tag)
+		if err != nil {
+			t.Errorf("%#v: %v", test.tag, err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("%#v: expected %v, got %v", test.tag, test.expected, s)
+		}
+	}
+}
+
+// TestSNBTQuoting tests that strings and keys are quoted only when needed, that the quote requiring the least
+// escaping is used, and that escaped strings are decoded again.
+func TestSNBTQuoting(t *testing.T) {
+	for _, test := range []struct {
+		value    string
+		expected string
+	}{
+		{"stone", `{stone:"stone"}`},
+		{"minecraft:stone", `{"minecraft:stone":"minecraft:stone"}`},
+		{"", `{"":""}`},
+		{`say "hi"`, `{'say "hi"':'say "hi"'}`},
+		{`it's`, `{"it's":"it's"}`},
+		{`"it's"`, `{"\"it's\"":"\"it's\""}`},
+		{`back\slash`, `{"back\\slash":"back\\slash"}`},
+		{"§aé", `{"§aé":"§aé"}`},
+	} {
+		s, err := MarshalSNBT(compoundOf(test.value, String(test.value)))
+		if err != nil {
+			t.Errorf("%q: %v", test.value, err)
+			continue
+		}
+		if s != test.expected {
+			t.Errorf("%q: expected %v, got %v", test.value, test.expected, s)
+			continue
+		}
+		var tag Tag
+		if err := UnmarshalSNBT([]byte(s), &tag); err != nil {
+			t.Errorf("%q: %v", test.value, err)
+			continue
+		}
+		if expected := compoundOf(test.value, String(test.value)); !reflect.DeepEqual(tag, expected) {
+			t.Errorf("%q: expected %#v, got %#v", test.value, expected, tag)
+		}
+	}
+
+	// Keys that consist only of unquoted characters are written without quotes, even if they look like a
+	// number, as keys are always strings.
+	if s, _ := MarshalSNBT(compoundOf("1b", Byte(1), "a.b-c_d+e", Byte(2))); s != "{1b:1b,a.b-c_d+e:2b}" {
+		t.Errorf("unquoted keys: got %v", s)
+	}
+	for _, s := range []string{`"unterminated`, `'\n'`, `"\`} {
+		var tag Tag
+		if err := UnmarshalSNBT([]byte(s), &tag); err == nil {
+			t.Errorf("%v: expected error, got %#v", s, tag)
+		}
+	}
+}
+
+// TestSNBTRoundTrip tests that a nested compound encoded in the binary format, converted to SNBT and back,
+// encodes to exactly the same data.
+func TestSNBTRoundTrip(t *testing.T) {
+	list := NewList(TagCompound)
+	_ = list.Add(compoundOf("id", String("minecraft:diamond_sword"), "Count", Byte(1), "tag", compoundOf(
+		"Damage", Int(12),
+		"display", compoundOf("Name", String(`{"text":"Sword"}`), "Lore", &List{ElemType: TagString, Tags: []Tag{String("it's sharp")}}),
+	)))
+	_ = list.Add(compoundOf("id", String("minecraft:air"), "Count", Byte(0)))
+	root := compoundOf(
+		"DataVersion", Int(2730),
+		"Pos", &List{ElemType: TagDouble, Tags: []Tag{Double(0.5), Double(64), Double(-12.25)}},
+		"Rotation", &List{ElemType: TagFloat, Tags: []Tag{Float(90), Float(-0.5)}},
+		"UUID", IntArray{1, -2, 3, -4},
+		"Heights", LongArray{1 << 40, -1},
+		"Data", ByteArray{0, 1, 0xff},
+		"Items", list,
+		"Empty", NewList(TagEnd),
+		"Time", Long(-5),
+		"Fire", Short(-20),
+		"weird key", String("a\\b"),
+	)
+
+	buf := &bytes.Buffer{}
+	if err := NewEncoderWithEncoding(buf, BigEndian).EncodeTag("", root); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	data := buf.Bytes()
+
+	_, decoded, err := NewDecoderWithEncoding(bytes.NewReader(data), BigEndian).DecodeTag()
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	s, err := MarshalSNBT(decoded)
+	if err != nil {
+		t.Fatalf("marshal SNBT: %v", err)
+	}
+	var parsed Tag
+	if err := UnmarshalSNBT([]byte(s), &parsed); err != nil {
+		t.Fatalf("unmarshal SNBT %v: %v", s, err)
+	}
+	buf = &bytes.Buffer{}
+	if err := NewEncoderWithEncoding(buf, BigEndian).EncodeTag("", parsed); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("binary data changed after SNBT round trip through %v:\nexpected %x\ngot      %x", s, data, buf.Bytes())
+	}
+}
+
+// compoundOf returns a compound holding the keys and tags passed, in order.
+func compoundOf(kv ...interface{}) *Compound {
+	c := NewCompound()
+	for i := 0; i < len(kv); i += 2 {
+		c.Set(kv[i].(string), kv[i+1].(Tag))
+	}
+	return c
+}
@@ -0,0 +1,295 @@
+package nbt
+
+import (
+	"io"
+)
+
+// TokenKind is the kind of a Token read by a TokenReader.
+type TokenKind byte
+
+const (
+	// TokenValue is a tag that is neither a compound nor a list, such as a TAG_Int or TAG_ByteArray. Its
+	// value is held by the Value field of the token.
+	TokenValue TokenKind = iota
+	// TokenBeginCompound is the start of a TAG_Compound. It is followed by the tokens of its entries and a
+	// TokenEndCompound.
+	TokenBeginCompound
+	// TokenEndCompound is the end of the most recently begun TAG_Compound.
+	TokenEndCompound
+	// TokenBeginList is the start of a TAG_List. It is followed by the tokens of its elements and a
+	// TokenEndList.
+	TokenBeginList
+	// TokenEndList is the end of the most recently begun TAG_List.
+	TokenEndList
+)
+
+// Token is a single part of an NBT object read by a TokenReader.
+type Token struct {
+	// Kind is the kind of the token.
+	Kind TokenKind
+	// Type is the type of the tag the token is part of. It is TagCompound or TagList for tokens that begin or
+	// end a compound or list.
+	Type TagType
+	// Name is the name of the tag. It is empty for elements of lists and for the end of compounds and lists.
+	Name string
+	// Value holds the value of the tag if the kind of the token is TokenValue.
+	Value Tag
+	// ElemType and Len are the element type and length of a list if the kind of the token is
+	// TokenBeginList.
+	ElemType TagType
+	Len      int32
+}
+
+// TokenReader reads an NBT object from a stream one token at a time, without materialising the entire object.
+// Subtrees that are not needed may be skipped using Skip, which reads past them without decoding their values.
+type TokenReader struct {
+	d       *Decoder
+	stack   []tokenFrame
+	started bool
+}
+
+// tokenFrame is a compound or list that was begun but not yet ended by a TokenReader.
+type tokenFrame struct {
+	list      bool
+	elemType  byte
+	remaining int32
+}
+
+// NewTokenReader returns a new TokenReader that reads from the reader passed. The BigEndian encoding is used.
+func NewTokenReader(r io.Reader) *TokenReader {
+	return &TokenReader{d: NewDecoder(r)}
+}
+
+// NewTokenReaderWithEncoding returns a new TokenReader that reads from the reader passed using the encoding
+// passed.
+func NewTokenReaderWithEncoding(r io.Reader, encoding Encoding) *TokenReader {
+	return &TokenReader{d: NewDecoderWithEncoding(r, encoding)}
+}
+
+// Depth returns the amount of compounds and lists that were begun but not yet ended.
+func (t *TokenReader) Depth() int {
+	return len(t.stack)
+}
+
+// Next reads the next token from the stream. io.EOF is returned once the entire root tag has been read.
+func (t *TokenReader) Next() (Token, error) {
+	if len(t.stack) == 0 {
+		if t.started {
+			return Token{}, io.EOF
+		}
+		t.started = true
+		tagType, name, err := t.d.tag()
+		if err != nil {
+			return Token{}, err
+		}
+		return t.begin(tagType, name)
+	}
+	top := &t.stack[len(t.stack)-1]
+	if top.list {
+		if top.remaining <= 0 {
+			t.pop()
+			return Token{Kind: TokenEndList, Type: TagList}, nil
+		}
+		top.remaining--
+		return t.begin(top.elemType, "")
+	}
+	tagType, name, err := t.d.tag()
+	if err != nil {
+		return Token{}, err
+	}
+	if tagType == tagEnd {
+		t.pop()
+		return Token{Kind: TokenEndCompound, Type: TagCompound}, nil
+	}
+	return t.begin(tagType, name)
+}
+
+// Skip skips the remainder of the compound or list that was most recently begun, up to and including its end,
+// without decoding any of its values. Calling Skip directly after Next returned a TokenBeginCompound or
+// TokenBeginList therefore skips that entire compound or list. If no compound or list is open, Skip does
+// nothing.
+func (t *TokenReader) Skip() error {
+	if len(t.stack) == 0 {
+		return nil
+	}
+	top := t.stack[len(t.stack)-1]
+	var err error
+	if top.list {
+		err = t.skipListElements(top.elemType, top.remaining)
+	} else {
+		err = t.skipCompoundEntries()
+	}
+	if err != nil {
+		return err
+	}
+	t.pop()
+	return nil
+}
+
+// begin reads the tag with the type and name passed. Compounds and lists are pushed on the stack, and any other
+// tag is read entirely.
+func (t *TokenReader) begin(tagType byte, name string) (Token, error) {
+	switch tagType {
+	case tagStruct:
+		if err := t.push(tokenFrame{}); err != nil {
+			return Token{}, err
+		}
+		return Token{Kind: TokenBeginCompound, Type: TagCompound, Name: name}, nil
+	case tagSlice:
+		elemType, length, err := t.listHeader()
+		if err != nil {
+			return Token{}, err
+		}
+		if err := t.push(tokenFrame{list: true, elemType: elemType, remaining: length}); err != nil {
+			return Token{}, err
+		}
+		return Token{Kind: TokenBeginList, Type: TagList, Name: name, ElemType: TagType(elemType), Len: length}, nil
+	}
+	value, err := t.d.readTag(TagType(tagType))
+	if err != nil {
+		return Token{}, err
+	}
+	return Token{Kind: TokenValue, Type: TagType(tagType), Name: name, Value: value}, nil
+}
+
+// listHeader reads the element type and length of a list.
+func (t *TokenReader) listHeader() (byte, int32, error) {
+	elemType, err := t.d.r.ReadByte()
+	if err != nil {
+		return 0, 0, BufferOverrunError{Op: "List"}
+	}
+	if !tagExists(elemType) {
+		return 0, 0, UnknownTagError{Off: t.d.r.off, TagType: elemType, Op: "List"}
+	}
+	length, err := t.d.Encoding.Int32(t.d.r)
+	if err != nil {
+		return 0, 0, err
+	}
+	if length > 0 && elemType == tagEnd {
+		return 0, 0, UnexpectedTagError{Off: t.d.r.off, TagType: tagEnd}
+	}
+	return elemType, length, nil
+}
+
+// push pushes a frame on the stack of open compounds and lists.
+func (t *TokenReader) push(f tokenFrame) error {
+	if len(t.stack) >= maximumNestingDepth {
+		return MaximumDepthReachedError{}
+	}
+	t.stack = append(t.stack, f)
+	t.d.depth = len(t.stack)
+	return nil
+}
+
+// pop pops the top frame off the stack of open compounds and lists.
+func (t *TokenReader) pop() {
+	t.stack = t.stack[:len(t.stack)-1]
+	t.d.depth = len(t.stack)
+}
+
+// skipCompoundEntries skips all remaining entries of a compound, including the TAG_End closing it.
+func (t *TokenReader) skipCompoundEntries() error {
+	for {
+		tagType, _, err := t.d.tag()
+		if err != nil {
+			return err
+		}
+		if tagType == tagEnd {
+			return nil
+		}
+		if err := t.skipPayload(tagType); err != nil {
+			return err
+		}
+	}
+}
+
+// skipListElements skips the amount of list elements with the type passed.
+func (t *TokenReader) skipListElements(elemType byte, n int32) error {
+	for i := int32(0); i < n; i++ {
+		if err := t.skipPayload(elemType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipPayload skips the payload of a tag with the type passed.
+func (t *TokenReader) skipPayload(tagType byte) error {
+	d := t.d
+	var err error
+	switch tagType {
+	case tagByte:
+		_, err = d.r.ReadByte()
+	case tagInt16:
+		_, err = d.Encoding.Int16(d.r)
+	case tagInt32:
+		_, err = d.Encoding.Int32(d.r)
+	case tagInt64:
+		_, err = d.Encoding.Int64(d.r)
+	case tagFloat32:
+		_, err = d.Encoding.Float32(d.r)
+	case tagFloat64:
+		_, err = d.Encoding.Float64(d.r)
+	case tagString:
+		_, err = d.Encoding.String(d.r)
+	case tagByteArray, tagInt32Array, tagInt64Array:
+		err = t.skipArray(tagType)
+	case tagSlice:
+		elemType, length, err := t.listHeader()
+		if err != nil {
+			return err
+		}
+		if err := t.push(tokenFrame{list: true}); err != nil {
+			return err
+		}
+		defer t.pop()
+		return t.skipListElements(elemType, length)
+	case tagStruct:
+		if err := t.push(tokenFrame{}); err != nil {
+			return err
+		}
+		defer t.pop()
+		return t.skipCompoundEntries()
+	default:
+		return UnknownTagError{Off: d.r.off, TagType: tagType, Op: "Skip"}
+	}
+	return err
+}
+
+// skipArray skips the payload of a byte, int or long array.
+func (t *TokenReader) skipArray(tagType byte) error {
+	d := t.d
+	length, err := d.Encoding.Int32(d.r)
+	if err != nil {
+		return err
+	}
+	if length < 0 {
+		return InvalidArraySizeError{Off: d.r.off, Op: "Skip", NBTLength: int(length)}
+	}
+	if d.Encoding == NetworkLittleEndian && tagType != tagByteArray {
+		// Integers are variable in size in the network encoding, so they must be read one by one.
+		for i := int32(0); i < length; i++ {
+			if tagType == tagInt32Array {
+				_, err = d.Encoding.Int32(d.r)
+			} else {
+				_, err = d.Encoding.Int64(d.r)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	size := int(length)
+	switch tagType {
+	case tagInt32Array:
+		size *= 4
+	case tagInt64Array:
+		size *= 8
+	}
+	// Discard the data in small chunks, so that skipping large arrays does not allocate them entirely.
+	if _, err := io.CopyN(io.Discard, d.r, int64(size)); err != nil {
+		return BufferOverrunError{Op: "Skip"}
+	}
+	return nil
+}
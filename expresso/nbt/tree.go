@@ -0,0 +1,565 @@
+package nbt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TagType is the type of an NBT tag, as written in front of every tag in its binary representation.
+type TagType byte
+
+// The types of all NBT tags. TAG_End only marks the end of a compound and is never a tag on its own.
+const (
+	TagEnd       = TagType(tagEnd)
+	TagByte      = TagType(tagByte)
+	TagShort     = TagType(tagInt16)
+	TagInt       = TagType(tagInt32)
+	TagLong      = TagType(tagInt64)
+	TagFloat     = TagType(tagFloat32)
+	TagDouble    = TagType(tagFloat64)
+	TagByteArray = TagType(tagByteArray)
+	TagString    = TagType(tagString)
+	TagList      = TagType(tagSlice)
+	TagCompound  = TagType(tagStruct)
+	TagIntArray  = TagType(tagInt32Array)
+	TagLongArray = TagType(tagInt64Array)
+)
+
+// String returns the name of the tag type, such as TAG_Compound.
+func (t TagType) String() string {
+	if !tagExists(byte(t)) {
+		return fmt.Sprintf("TagType(%d)", byte(t))
+	}
+	return tagName(byte(t))
+}
+
+// Tag is a single NBT tag of an exact type. Unlike the values produced by Unmarshal, tags keep the difference
+// between types that decode to the same Go value, such as the element type of empty lists, and compound tags
+// keep the order of their entries, so that a tag tree decoded and encoded again produces identical data.
+//
+// Tags are one of Byte, Short, Int, Long, Float, Double, ByteArray, String, *List, *Compound, IntArray and
+// LongArray.
+type Tag interface {
+	// Type returns the type of the tag.
+	Type() TagType
+}
+
+type (
+	// Byte is a TAG_Byte.
+	Byte byte
+	// Short is a TAG_Short.
+	Short int16
+	// Int is a TAG_Int.
+	Int int32
+	// Long is a TAG_Long.
+	Long int64
+	// Float is a TAG_Float.
+	Float float32
+	// Double is a TAG_Double.
+	Double float64
+	// ByteArray is a TAG_ByteArray.
+	ByteArray []byte
+	// String is a TAG_String.
+	String string
+	// IntArray is a TAG_IntArray.
+	IntArray []int32
+	// LongArray is a TAG_LongArray.
+	LongArray []int64
+)
+
+// Type ...
+func (Byte) Type() TagType { return TagByte }
+
+// Type ...
+func (Short) Type() TagType { return TagShort }
+
+// Type ...
+func (Int) Type() TagType { return TagInt }
+
+// Type ...
+func (Long) Type() TagType { return TagLong }
+
+// Type ...
+func (Float) Type() TagType { return TagFloat }
+
+// Type ...
+func (Double) Type() TagType { return TagDouble }
+
+// Type ...
+func (ByteArray) Type() TagType { return TagByteArray }
+
+// Type ...
+func (String) Type() TagType { return TagString }
+
+// Type ...
+func (IntArray) Type() TagType { return TagIntArray }
+
+// Type ...
+func (LongArray) Type() TagType { return TagLongArray }
+
+// List is a TAG_List. All of its tags must be of the element type of the list.
+type List struct {
+	// ElemType is the type of the tags in the list. It is kept for empty lists, which may have any element
+	// type, TAG_End included.
+	ElemType TagType
+	// Tags holds the tags in the list.
+	Tags []Tag
+}
+
+// NewList returns a new empty list with the element type passed.
+func NewList(elemType TagType) *List {
+	return &List{ElemType: elemType}
+}
+
+// Type ...
+func (*List) Type() TagType { return TagList }
+
+// Len returns the amount of tags in the list.
+func (l *List) Len() int {
+	return len(l.Tags)
+}
+
+// Index returns the tag at the index passed. False is returned if the index is out of range.
+func (l *List) Index(i int) (Tag, bool) {
+	if i < 0 || i >= len(l.Tags) {
+		return nil, false
+	}
+	return l.Tags[i], true
+}
+
+// Add adds a tag to the end of the list. If the list is empty, its element type is set to the type of the
+// tag. An error is returned if the tag is of a different type than the other tags of the list.
+func (l *List) Add(t Tag) error {
+	if len(l.Tags) == 0 {
+		l.ElemType = t.Type()
+	} else if t.Type() != l.ElemType {
+		return fmt.Errorf("nbt: cannot add %v to list of %v", t.Type(), l.ElemType)
+	}
+	l.Tags = append(l.Tags, t)
+	return nil
+}
+
+// Compound is a TAG_Compound. It keeps the order in which its entries were added.
+type Compound struct {
+	keys   []string
+	values map[string]Tag
+}
+
+// NewCompound returns a new empty compound.
+func NewCompound() *Compound {
+	return &Compound{values: make(map[string]Tag)}
+}
+
+// Type ...
+func (*Compound) Type() TagType { return TagCompound }
+
+// Len returns the amount of entries in the compound.
+func (c *Compound) Len() int {
+	return len(c.keys)
+}
+
+// Keys returns the keys of all entries in the compound, in order.
+func (c *Compound) Keys() []string {
+	return append([]string(nil), c.keys...)
+}
+
+// Tag returns the tag stored under the key passed. False is returned if the compound has no such entry.
+func (c *Compound) Tag(key string) (Tag, bool) {
+	t, ok := c.values[key]
+	return t, ok
+}
+
+// Set stores a tag under the key passed. If the compound already has an entry with the key, its tag is
+// replaced and the entry keeps its position. Otherwise, the entry is added to the end.
+func (c *Compound) Set(key string, t Tag) {
+	if c.values == nil {
+		c.values = make(map[string]Tag)
+	}
+	if _, ok := c.values[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.values[key] = t
+}
+
+// Delete removes the entry with the key passed from the compound, if it exists.
+func (c *Compound) Delete(key string) {
+	if _, ok := c.values[key]; !ok {
+		return
+	}
+	delete(c.values, key)
+	for i, k := range c.keys {
+		if k == key {
+			c.keys = append(c.keys[:i], c.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Get looks up a tag nested in the compound using a path such as Inventory[0].tag.display.Name. See the Get
+// function for the path syntax.
+func (c *Compound) Get(path string) (Tag, error) {
+	return Get(c, path)
+}
+
+// Get looks up a tag nested in the tag passed using a path. Keys of compounds are separated by dots and
+// indices of lists and arrays are written in square brackets, such as Inventory[0].tag.display.Name. Keys that
+// contain dots or square brackets may be quoted using double quotes, such as "minecraft:item.name".
+// The tags at indices of arrays are returned as Byte, Int or Long.
+func Get(t Tag, path string) (Tag, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	current := t
+	for i, seg := range segments {
+		at := formatPath(segments[:i])
+		if seg.key != nil {
+			c, ok := current.(*Compound)
+			if !ok {
+				return nil, fmt.Errorf("nbt: %v at %q is not a compound", current.Type(), at)
+			}
+			if current, ok = c.Tag(*seg.key); !ok {
+				return nil, fmt.Errorf("nbt: no tag %q in compound at %q", *seg.key, at)
+			}
+			continue
+		}
+		var length int
+		switch v := current.(type) {
+		case *List:
+			length = len(v.Tags)
+		case ByteArray:
+			length = len(v)
+		case IntArray:
+			length = len(v)
+		case LongArray:
+			length = len(v)
+		default:
+			return nil, fmt.Errorf("nbt: %v at %q is not a list or array", current.Type(), at)
+		}
+		if seg.index < 0 || seg.index >= length {
+			return nil, fmt.Errorf("nbt: index %v out of range for length %v at %q", seg.index, length, at)
+		}
+		switch v := current.(type) {
+		case *List:
+			current = v.Tags[seg.index]
+		case ByteArray:
+			current = Byte(v[seg.index])
+		case IntArray:
+			current = Int(v[seg.index])
+		case LongArray:
+			current = Long(v[seg.index])
+		}
+	}
+	return current, nil
+}
+
+// pathSegment is a single segment of a path passed to Get. It is either a compound key or a list index.
+type pathSegment struct {
+	key   *string
+	index int
+}
+
+// parsePath parses a path passed to Get into its segments.
+func parsePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	for i := 0; i < len(path); {
+		switch c := path[i]; {
+		case c == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("nbt: unterminated index in path %q", path)
+			}
+			index, err := strconv.Atoi(path[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("nbt: invalid index in path %q: %w", path, err)
+			}
+			segments = append(segments, pathSegment{index: index})
+			i += end + 1
+		case c == '.' && len(segments) > 0:
+			i++
+			if i == len(path) || path[i] == '.' || path[i] == '[' {
+				return nil, fmt.Errorf("nbt: expected key after '.' in path %q", path)
+			}
+		case c == '"':
+			end := strings.IndexByte(path[i+1:], '"')
+			if end == -1 {
+				return nil, fmt.Errorf("nbt: unterminated quoted key in path %q", path)
+			}
+			key := path[i+1 : i+1+end]
+			segments = append(segments, pathSegment{key: &key})
+			i += end + 2
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end == -1 {
+				end = len(path) - i
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("nbt: expected key in path %q", path)
+			}
+			key := path[i : i+end]
+			segments = append(segments, pathSegment{key: &key})
+			i += end
+		}
+	}
+	return segments, nil
+}
+
+// formatPath formats path segments back to a path, used for error messages.
+func formatPath(segments []pathSegment) string {
+	b := strings.Builder{}
+	for _, seg := range segments {
+		if seg.key == nil {
+			b.WriteString("[" + strconv.Itoa(seg.index) + "]")
+			continue
+		}
+		if b.Len() != 0 {
+			b.WriteByte('.')
+		}
+		if strings.ContainsAny(*seg.key, ".[]\"") {
+			b.WriteString(`"` + *seg.key + `"`)
+		} else {
+			b.WriteString(*seg.key)
+		}
+	}
+	return b.String()
+}
+
+// DecodeTag reads the next NBT object from the input stream as a tag tree, and returns it along with the name
+// of its root tag.
+func (d *Decoder) DecodeTag() (string, Tag, error) {
+	tagType, name, err := d.tag()
+	if err != nil {
+		return "", nil, err
+	}
+	t, err := d.readTag(TagType(tagType))
+	return name, t, err
+}
+
+// readTag reads the payload of a tag with the type passed.
+func (d *Decoder) readTag(tagType TagType) (Tag, error) {
+	switch tagType {
+	case TagByte:
+		v, err := d.r.ReadByte()
+		if err != nil {
+			return nil, BufferOverrunError{Op: "Byte"}
+		}
+		return Byte(v), nil
+	case TagShort:
+		v, err := d.Encoding.Int16(d.r)
+		return Short(v), err
+	case TagInt:
+		v, err := d.Encoding.Int32(d.r)
+		return Int(v), err
+	case TagLong:
+		v, err := d.Encoding.Int64(d.r)
+		return Long(v), err
+	case TagFloat:
+		v, err := d.Encoding.Float32(d.r)
+		return Float(v), err
+	case TagDouble:
+		v, err := d.Encoding.Float64(d.r)
+		return Double(v), err
+	case TagString:
+		v, err := d.Encoding.String(d.r)
+		return String(v), err
+	case TagByteArray:
+		length, err := d.Encoding.Int32(d.r)
+		if err != nil {
+			return nil, err
+		}
+		data, err := consumeN(int(length), d.r)
+		if err != nil {
+			return nil, BufferOverrunError{Op: "ByteArray"}
+		}
+		return ByteArray(append([]byte(nil), data...)), nil
+	case TagIntArray:
+		length, err := d.Encoding.Int32(d.r)
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return nil, InvalidArraySizeError{Off: d.r.off, Op: "Int32Array", NBTLength: int(length)}
+		}
+		v := make(IntArray, 0, minCapacity(length))
+		for i := int32(0); i < length; i++ {
+			n, err := d.Encoding.Int32(d.r)
+			if err != nil {
+				return nil, err
+			}
+			v = append(v, n)
+		}
+		return v, nil
+	case TagLongArray:
+		length, err := d.Encoding.Int32(d.r)
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return nil, InvalidArraySizeError{Off: d.r.off, Op: "Int64Array", NBTLength: int(length)}
+		}
+		v := make(LongArray, 0, minCapacity(length))
+		for i := int32(0); i < length; i++ {
+			n, err := d.Encoding.Int64(d.r)
+			if err != nil {
+				return nil, err
+			}
+			v = append(v, n)
+		}
+		return v, nil
+	case TagList:
+		if d.depth++; d.depth >= maximumNestingDepth {
+			return nil, MaximumDepthReachedError{}
+		}
+		defer func() { d.depth-- }()
+
+		elemType, err := d.r.ReadByte()
+		if err != nil {
+			return nil, BufferOverrunError{Op: "List"}
+		}
+		if !tagExists(elemType) {
+			return nil, UnknownTagError{Off: d.r.off, TagType: elemType, Op: "List"}
+		}
+		length, err := d.Encoding.Int32(d.r)
+		if err != nil {
+			return nil, err
+		}
+		l := &List{ElemType: TagType(elemType), Tags: make([]Tag, 0, minCapacity(length))}
+		for i := int32(0); i < length; i++ {
+			if elemType == tagEnd {
+				return nil, UnexpectedTagError{Off: d.r.off, TagType: tagEnd}
+			}
+			t, err := d.readTag(TagType(elemType))
+			if err != nil {
+				return nil, err
+			}
+			l.Tags = append(l.Tags, t)
+		}
+		return l, nil
+	case TagCompound:
+		d.depth++
+		defer func() { d.depth-- }()
+
+		c := NewCompound()
+		for {
+			nestedType, name, err := d.tag()
+			if err != nil {
+				return nil, err
+			}
+			if nestedType == tagEnd {
+				return c, nil
+			}
+			t, err := d.readTag(TagType(nestedType))
+			if err != nil {
+				return nil, err
+			}
+			c.Set(name, t)
+		}
+	case TagEnd:
+		return nil, UnexpectedTagError{Off: d.r.off, TagType: tagEnd}
+	}
+	return nil, UnknownTagError{Off: d.r.off, TagType: byte(tagType), Op: "Match"}
+}
+
+// minCapacity returns the capacity to allocate for a list or array with the length passed. The capacity is
+// limited so that a malicious length cannot cause a large allocation up front.
+func minCapacity(length int32) int {
+	if length < 0 {
+		return 0
+	}
+	if length > 1024 {
+		return 1024
+	}
+	return int(length)
+}
+
+// EncodeTag writes the tag tree passed to the NBT output stream of the encoder, with the root tag name passed.
+func (e *Encoder) EncodeTag(name string, t Tag) error {
+	if err := e.writeTag(byte(t.Type()), name); err != nil {
+		return err
+	}
+	return e.writeTagPayload(t)
+}
+
+// writeTagPayload writes the payload of the tag passed, without its type and name.
+func (e *Encoder) writeTagPayload(t Tag) error {
+	switch v := t.(type) {
+	case Byte:
+		return e.w.WriteByte(byte(v))
+	case Short:
+		return e.Encoding.WriteInt16(e.w, int16(v))
+	case Int:
+		return e.Encoding.WriteInt32(e.w, int32(v))
+	case Long:
+		return e.Encoding.WriteInt64(e.w, int64(v))
+	case Float:
+		return e.Encoding.WriteFloat32(e.w, float32(v))
+	case Double:
+		return e.Encoding.WriteFloat64(e.w, float64(v))
+	case String:
+		return e.Encoding.WriteString(e.w, string(v))
+	case ByteArray:
+		if err := e.Encoding.WriteInt32(e.w, int32(len(v))); err != nil {
+			return err
+		}
+		if _, err := e.w.Write(v); err != nil {
+			return FailedWriteError{Op: "WriteByteArray", Off: e.w.off}
+		}
+		return nil
+	case IntArray:
+		if err := e.Encoding.WriteInt32(e.w, int32(len(v))); err != nil {
+			return err
+		}
+		for _, n := range v {
+			if err := e.Encoding.WriteInt32(e.w, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	case LongArray:
+		if err := e.Encoding.WriteInt32(e.w, int32(len(v))); err != nil {
+			return err
+		}
+		for _, n := range v {
+			if err := e.Encoding.WriteInt64(e.w, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *List:
+		e.depth++
+		defer func() { e.depth-- }()
+		if e.depth >= maximumNestingDepth {
+			return MaximumDepthReachedError{}
+		}
+		if err := e.w.WriteByte(byte(v.ElemType)); err != nil {
+			return FailedWriteError{Off: e.w.off, Op: "WriteSlice", Err: err}
+		}
+		if err := e.Encoding.WriteInt32(e.w, int32(len(v.Tags))); err != nil {
+			return err
+		}
+		for _, nested := range v.Tags {
+			if nested.Type() != v.ElemType {
+				return fmt.Errorf("nbt: list of %v holds %v", v.ElemType, nested.Type())
+			}
+			if err := e.writeTagPayload(nested); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *Compound:
+		e.depth++
+		defer func() { e.depth-- }()
+		for _, key := range v.keys {
+			nested := v.values[key]
+			if err := e.writeTag(byte(nested.Type()), key); err != nil {
+				return err
+			}
+			if err := e.writeTagPayload(nested); err != nil {
+				return err
+			}
+		}
+		return e.w.WriteByte(tagEnd)
+	}
+	return fmt.Errorf("nbt: unknown tag %T", t)
+}
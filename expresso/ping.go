@@ -0,0 +1,271 @@
+package expresso
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/justtaldevelops/expresso/expresso/protocol"
+	"github.com/justtaldevelops/expresso/expresso/protocol/packet"
+	"github.com/justtaldevelops/expresso/expresso/text"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// defaultPort is the default port of Minecraft servers, which is used if an address has no port and no SRV
+// record.
+const defaultPort = 25565
+
+// Ping requests the status of the Minecraft server at the address passed, as shown in the server list, and
+// measures the latency of the connection to it. If the address has no port, the port and host are looked up
+// in the _minecraft._tcp SRV record of the address, or the default port is used if there is none. Servers
+// that do not respond to the status request, such as those older than 1.7, are pinged using the legacy ping,
+// of which the response holds only the version, player counts and description. The context passed may be used
+// to cancel the ping or to set a deadline.
+func Ping(ctx context.Context, address string) (Status, time.Duration, error) {
+	host, port, dialAddress, err := resolveAddress(ctx, address)
+	if err != nil {
+		return Status{}, 0, fmt.Errorf("ping %v: %w", address, err)
+	}
+	status, latency, err := ping(ctx, host, port, dialAddress)
+	if err == nil {
+		return status, latency, nil
+	}
+	var opErr *net.OpError
+	if ctx.Err() != nil || (errors.As(err, &opErr) && opErr.Op == "dial") {
+		// The legacy ping would fail for the same reason, so it is not attempted.
+		return Status{}, 0, fmt.Errorf("ping %v: %w", address, err)
+	}
+	status, latency, legacyErr := legacyPing(ctx, host, port, dialAddress)
+	if legacyErr != nil {
+		return Status{}, 0, fmt.Errorf("ping %v: %w (legacy ping: %v)", address, err, legacyErr)
+	}
+	return status, latency, nil
+}
+
+// ping requests the status of the server at the address passed using the status request and measures the
+// latency using the status ping.
+func ping(ctx context.Context, host string, port uint16, address string) (Status, time.Duration, error) {
+	conn, done, err := dialPing(ctx, address)
+	if err != nil {
+		return Status{}, 0, err
+	}
+	defer done()
+
+	c := &pingConn{conn: conn, r: protocol.NewReader(bufio.NewReader(conn))}
+	if err := c.writePacket(&packet.Handshake{Protocol: protocol.CurrentProtocol, Address: host, Port: int16(port), NextState: 0x01}); err != nil {
+		return Status{}, 0, err
+	}
+	if err := c.writePacket(&packet.ClientStatusRequest{}); err != nil {
+		return Status{}, 0, err
+	}
+	resp := &packet.ServerStatusResponse{}
+	if err := c.readPacket(resp); err != nil {
+		return Status{}, 0, err
+	}
+	var status Status
+	if err := json.Unmarshal([]byte(resp.Status), &status); err != nil {
+		return Status{}, 0, fmt.Errorf("decode status: %w", err)
+	}
+
+	start := time.Now()
+	if err := c.writePacket(&packet.ClientStatusPing{Payload: start.UnixMilli()}); err != nil {
+		return Status{}, 0, err
+	}
+	if err := c.readPacket(&packet.ServerStatusPong{}); err != nil {
+		return Status{}, 0, err
+	}
+	return status, time.Since(start), nil
+}
+
+// legacyPing requests the status of the server at the address passed using the ping of servers older than
+// 1.7, and measures the time until the response as latency.
+func legacyPing(ctx context.Context, host string, port uint16, address string) (Status, time.Duration, error) {
+	conn, done, err := dialPing(ctx, address)
+	if err != nil {
+		return Status{}, 0, err
+	}
+	defer done()
+
+	buf := &bytes.Buffer{}
+	buf.Write([]byte{0xfe, 0x01, 0xfa})
+	writeLegacyString(buf, "MC|PingHost")
+	_ = binary.Write(buf, binary.BigEndian, uint16(7+len(utf16.Encode([]rune(host)))*2))
+	// The protocol version of 1.6.2, which is the last version that used this ping.
+	buf.WriteByte(74)
+	writeLegacyString(buf, host)
+	_ = binary.Write(buf, binary.BigEndian, int32(port))
+
+	start := time.Now()
+	if _, err := conn.Write(buf.Bytes()); err != nil {
+		return Status{}, 0, err
+	}
+	r := bufio.NewReader(conn)
+	if id, err := r.ReadByte(); err != nil {
+		return Status{}, 0, err
+	} else if id != 0xff {
+		return Status{}, 0, fmt.Errorf("unexpected legacy ping response %#x", id)
+	}
+	latency := time.Since(start)
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return Status{}, 0, err
+	}
+	chars := make([]uint16, length)
+	if err := binary.Read(r, binary.BigEndian, chars); err != nil {
+		return Status{}, 0, err
+	}
+	status, err := parseLegacyStatus(string(utf16.Decode(chars)))
+	return status, latency, err
+}
+
+// parseLegacyStatus parses the response to a legacy ping. Servers of 1.4 up to 1.6 respond with §1 followed
+// by the protocol, version, description, online and maximum players separated by NUL characters, while older
+// servers respond with the description, online and maximum players separated by section signs.
+func parseLegacyStatus(s string) (Status, error) {
+	var (
+		status         Status
+		online, max    string
+		description    string
+		protocolString string
+	)
+	if strings.HasPrefix(s, "§1\x00") {
+		parts := strings.Split(s, "\x00")
+		if len(parts) != 6 {
+			return Status{}, fmt.Errorf("invalid legacy ping response %q", s)
+		}
+		protocolString, status.Version.Name, description, online, max = parts[1], parts[2], parts[3], parts[4], parts[5]
+	} else {
+		parts := strings.Split(s, "§")
+		if len(parts) < 3 {
+			return Status{}, fmt.Errorf("invalid legacy ping response %q", s)
+		}
+		description, online, max = strings.Join(parts[:len(parts)-2], "§"), parts[len(parts)-2], parts[len(parts)-1]
+	}
+
+	var err error
+	if protocolString != "" {
+		if status.Version.Protocol, err = strconv.Atoi(protocolString); err != nil {
+			return Status{}, fmt.Errorf("invalid protocol in legacy ping response: %w", err)
+		}
+	}
+	if status.Players.Online, err = strconv.Atoi(online); err != nil {
+		return Status{}, fmt.Errorf("invalid online players in legacy ping response: %w", err)
+	}
+	if status.Players.Max, err = strconv.Atoi(max); err != nil {
+		return Status{}, fmt.Errorf("invalid max players in legacy ping response: %w", err)
+	}
+	status.Players.Sample = []Player{}
+	status.Description = text.FromLegacy(description, text.SectionSign)
+	return status, nil
+}
+
+// writeLegacyString writes a string as used in the legacy ping, which is its length in UTF-16 code units
+// followed by the UTF-16 code units in big endian.
+func writeLegacyString(buf *bytes.Buffer, s string) {
+	chars := utf16.Encode([]rune(s))
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(chars)))
+	_ = binary.Write(buf, binary.BigEndian, chars)
+}
+
+// resolveAddress resolves the address passed to the host and port sent in the handshake, and the address to
+// connect to. If the address has no port, the _minecraft._tcp SRV record of the host is used if it exists.
+func resolveAddress(ctx context.Context, address string) (host string, port uint16, dialAddress string, err error) {
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		host = strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
+		if net.ParseIP(host) == nil {
+			_, records, err := net.DefaultResolver.LookupSRV(ctx, "minecraft", "tcp", host)
+			if err == nil && len(records) > 0 {
+				target := strings.TrimSuffix(records[0].Target, ".")
+				return host, records[0].Port, net.JoinHostPort(target, strconv.Itoa(int(records[0].Port))), nil
+			}
+		}
+		return host, defaultPort, net.JoinHostPort(host, strconv.Itoa(defaultPort)), nil
+	}
+	n, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("invalid port %q", portString)
+	}
+	return host, uint16(n), address, nil
+}
+
+// dialPing connects to the address passed. The connection is closed when the context passed is done, or when
+// the function returned is called.
+func dialPing(ctx context.Context, address string) (net.Conn, func(), error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, nil, err
+	}
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-stop:
+		}
+	}()
+	return conn, func() {
+		close(stop)
+		_ = conn.Close()
+	}, nil
+}
+
+// pingConn is a connection to a server that is pinged. Packets are neither compressed nor encrypted in the
+// status state.
+type pingConn struct {
+	conn net.Conn
+	r    *protocol.Reader
+}
+
+// writePacket writes a packet to the connection.
+func (c *pingConn) writePacket(pk packet.Packet) error {
+	buf := &bytes.Buffer{}
+	w := protocol.NewWriter(buf)
+	id := pk.ID()
+	w.Varint32(&id)
+	pk.Marshal(w)
+
+	frame := &bytes.Buffer{}
+	length := int32(buf.Len())
+	protocol.NewWriter(frame).Varint32(&length)
+	_, _ = buf.WriteTo(frame)
+	_, err := c.conn.Write(frame.Bytes())
+	return err
+}
+
+// readPacket reads the packet passed from the connection. An error is returned if the next packet is a
+// different packet.
+func (c *pingConn) readPacket(pk packet.Packet) (err error) {
+	defer func() {
+		// The reader panics if reading fails.
+		if r := recover(); r != nil {
+			err = fmt.Errorf("read packet: %v", r)
+		}
+	}()
+	var length int32
+	c.r.Varint32(&length)
+	if length < 1 || length > 1<<21 {
+		return fmt.Errorf("invalid packet length %v", length)
+	}
+	b := make([]byte, length)
+	if _, err := io.ReadFull(c.r, b); err != nil {
+		return fmt.Errorf("read packet: %w", err)
+	}
+	r := protocol.NewReader(bytes.NewReader(b))
+	var id int32
+	r.Varint32(&id)
+	if id != pk.ID() {
+		return fmt.Errorf("expected packet %#x, got %#x", pk.ID(), id)
+	}
+	pk.Unmarshal(r)
+	return nil
+}
@@ -0,0 +1,170 @@
+package expresso
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"github.com/justtaldevelops/expresso/expresso/text"
+	"io"
+	"net"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// TestParseLegacyStatus tests that the responses of both legacy ping formats are parsed, and that invalid
+// responses are rejected.
+func TestParseLegacyStatus(t *testing.T) {
+	for _, test := range []struct {
+		s        string
+		expected Status
+		valid    bool
+	}{
+		{"§1\x0074\x001.6.2\x00§aA server\x003\x0020", Status{
+			Version:     Version{Name: "1.6.2", Protocol: 74},
+			Players:     Players{Online: 3, Max: 20, Sample: []Player{}},
+			Description: text.Text{Text: "A server", Color: text.Green},
+		}, true},
+		{"A server§3§20", Status{
+			Players:     Players{Online: 3, Max: 20, Sample: []Player{}},
+			Description: text.Text{Text: "A server"},
+		}, true},
+		{"§cRed§r server§0§10", Status{
+			Players: Players{Online: 0, Max: 10, Sample: []Player{}},
+			Description: text.Text{Extra: []text.Text{
+				{Text: "Red", Color: text.Red},
+				{Text: " server"},
+			}},
+		}, true},
+		{"§1\x0074\x001.6.2\x00motd\x003", Status{}, false},
+		{"§1\x00new\x001.6.2\x00motd\x003\x0020", Status{}, false},
+		{"motd§3", Status{}, false},
+		{"motd§many§20", Status{}, false},
+		{"", Status{}, false},
+	} {
+		status, err := parseLegacyStatus(test.s)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("%q: expected error, got %v", test.s, status)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.s, err)
+			continue
+		}
+		if status.String() != test.expected.String() {
+			t.Errorf("%q: expected %v, got %v", test.s, test.expected, status)
+		}
+	}
+}
+
+// TestResolveAddress tests that the host and port sent in the handshake are taken from the address, and that
+// the default port is used for IP addresses without a port.
+func TestResolveAddress(t *testing.T) {
+	for _, test := range []struct {
+		address, host string
+		port          uint16
+		dialAddress   string
+		valid         bool
+	}{
+		{"127.0.0.1:25566", "127.0.0.1", 25566, "127.0.0.1:25566", true},
+		{"127.0.0.1", "127.0.0.1", defaultPort, "127.0.0.1:25565", true},
+		{"[::1]:1", "::1", 1, "[::1]:1", true},
+		{"[::1]", "::1", defaultPort, "[::1]:25565", true},
+		{"::1", "::1", defaultPort, "[::1]:25565", true},
+		{"localhost:80", "localhost", 80, "localhost:80", true},
+		{"127.0.0.1:65536", "", 0, "", false},
+		{"127.0.0.1:port", "", 0, "", false},
+	} {
+		host, port, dialAddress, err := resolveAddress(context.Background(), test.address)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("%v: expected error, got %v", test.address, dialAddress)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", test.address, err)
+			continue
+		}
+		if host != test.host || port != test.port || dialAddress != test.dialAddress {
+			t.Errorf("%v: expected %v, %v and %v, got %v, %v and %v", test.address, test.host, test.port, test.dialAddress, host, port, dialAddress)
+		}
+	}
+}
+
+// TestWriteLegacyString tests that strings are written as their length followed by their UTF-16 code units,
+// with characters outside of the basic multilingual plane written as surrogate pairs.
+func TestWriteLegacyString(t *testing.T) {
+	for s, expected := range map[string][]byte{
+		"":           {0x00, 0x00},
+		"MC":         {0x00, 0x02, 0x00, 'M', 0x00, 'C'},
+		"§":          {0x00, 0x01, 0x00, 0xa7},
+		"\U0001f600": {0x00, 0x02, 0xd8, 0x3d, 0xde, 0x00},
+	} {
+		buf := &bytes.Buffer{}
+		writeLegacyString(buf, s)
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("%q: expected %x, got %x", s, expected, buf.Bytes())
+		}
+	}
+}
+
+// TestPingLegacyFallback tests that servers that close the connection on a status request are pinged using
+// the legacy ping, and that the legacy request holds the host and port pinged.
+func TestPingLegacyFallback(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	requests := make(chan []byte, 1)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			r := bufio.NewReader(conn)
+			if b, err := r.Peek(1); err != nil || b[0] != 0xfe {
+				// Servers older than 1.7 do not understand the handshake and close the connection.
+				_ = conn.Close()
+				continue
+			}
+			request := make([]byte, 3+2+22+2+1+2+18+4)
+			if _, err := io.ReadFull(r, request); err == nil {
+				requests <- request
+			}
+			chars := utf16.Encode([]rune("§1\x0074\x001.6.2\x00Old\x001\x002"))
+			_ = binary.Write(conn, binary.BigEndian, uint8(0xff))
+			_ = binary.Write(conn, binary.BigEndian, uint16(len(chars)))
+			_ = binary.Write(conn, binary.BigEndian, chars)
+			_ = conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, _, err := Ping(ctx, l.Addr().String())
+	if err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	if status.Version.Protocol != 74 || status.Players.Online != 1 || status.Players.Max != 2 || status.Description.Plain() != "Old" {
+		t.Errorf("unexpected status %v", status)
+	}
+
+	port := l.Addr().(*net.TCPAddr).Port
+	expected := &bytes.Buffer{}
+	expected.Write([]byte{0xfe, 0x01, 0xfa})
+	writeLegacyString(expected, "MC|PingHost")
+	_ = binary.Write(expected, binary.BigEndian, uint16(7+9*2))
+	expected.WriteByte(74)
+	writeLegacyString(expected, "127.0.0.1")
+	_ = binary.Write(expected, binary.BigEndian, int32(port))
+	if request := <-requests; !bytes.Equal(request, expected.Bytes()) {
+		t.Errorf("expected legacy request %x, got %x", expected.Bytes(), request)
+	}
+}
@@ -0,0 +1,445 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/justtaldevelops/expresso/expresso/text"
+	"sync"
+)
+
+// BlockEntity represents a block entity, also known as a tile entity. Block entities hold additional data for
+// blocks that can not be represented by a block state alone, such as the text on a sign or the items in a chest.
+type BlockEntity interface {
+	// ID returns the namespaced identifier of the block entity, such as minecraft:sign.
+	ID() string
+	// EncodeNBT encodes the block entity specific data into a map, excluding the identifier and position.
+	EncodeNBT() map[string]interface{}
+	// DecodeNBT decodes the block entity specific data from the map passed into the block entity.
+	DecodeNBT(data map[string]interface{})
+}
+
+var (
+	// blockEntityMu protects blockEntities from concurrent access.
+	blockEntityMu sync.RWMutex
+	// blockEntities maps namespaced identifiers to functions creating new block entities of that type.
+	blockEntities = map[string]func() BlockEntity{}
+)
+
+// RegisterBlockEntity registers a function that creates a new block entity for the identifier passed. Block
+// entities with this identifier decoded from NBT are then decoded into the block entity returned.
+func RegisterBlockEntity(id string, f func() BlockEntity) {
+	blockEntityMu.Lock()
+	defer blockEntityMu.Unlock()
+	blockEntities[id] = f
+}
+
+// init registers all default block entities.
+func init() {
+	RegisterBlockEntity("minecraft:sign", func() BlockEntity { return &Sign{} })
+	RegisterBlockEntity("minecraft:banner", func() BlockEntity { return &Banner{} })
+	RegisterBlockEntity("minecraft:skull", func() BlockEntity { return &Skull{} })
+	RegisterBlockEntity("minecraft:bed", func() BlockEntity { return &Bed{} })
+	for _, id := range []string{
+		"minecraft:chest", "minecraft:trapped_chest", "minecraft:barrel", "minecraft:shulker_box",
+		"minecraft:dispenser", "minecraft:dropper", "minecraft:hopper",
+	} {
+		id := id
+		RegisterBlockEntity(id, func() BlockEntity { return &Container{Kind: id} })
+	}
+}
+
+// blockEntityActions maps namespaced identifiers to the action used in the block entity data packet.
+var blockEntityActions = map[string]byte{
+	"minecraft:mob_spawner":     1,
+	"minecraft:command_block":   2,
+	"minecraft:beacon":          3,
+	"minecraft:skull":           4,
+	"minecraft:conduit":         5,
+	"minecraft:banner":          6,
+	"minecraft:structure_block": 7,
+	"minecraft:end_gateway":     8,
+	"minecraft:sign":            9,
+	"minecraft:bed":             11,
+	"minecraft:jigsaw":          12,
+	"minecraft:campfire":        13,
+	"minecraft:beehive":         14,
+}
+
+// BlockEntityAction returns the action of the block entity used in the block entity data packet. Zero is
+// returned if the client has no specific action for the block entity.
+func BlockEntityAction(be BlockEntity) byte {
+	return blockEntityActions[be.ID()]
+}
+
+// EncodeBlockEntity encodes a block entity at the world position passed into its full NBT representation,
+// which includes the identifier and the position of the block entity.
+func EncodeBlockEntity(pos BlockPos, be BlockEntity) map[string]interface{} {
+	m := be.EncodeNBT()
+	if m == nil {
+		m = make(map[string]interface{})
+	}
+	m["id"] = be.ID()
+	m["x"], m["y"], m["z"] = pos.X(), pos.Y(), pos.Z()
+	return m
+}
+
+// DecodeBlockEntity decodes the full NBT representation of a block entity into its world position and the
+// block entity itself. Block entities with an unregistered identifier are decoded into a GenericBlockEntity.
+func DecodeBlockEntity(m map[string]interface{}) (BlockPos, BlockEntity, error) {
+	id, ok := m["id"].(string)
+	if !ok {
+		return BlockPos{}, nil, fmt.Errorf("block entity has no identifier")
+	}
+	x, xOk := m["x"].(int32)
+	y, yOk := m["y"].(int32)
+	z, zOk := m["z"].(int32)
+	if !xOk || !yOk || !zOk {
+		return BlockPos{}, nil, fmt.Errorf("block entity %v has no valid position", id)
+	}
+
+	data := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		switch k {
+		case "id", "x", "y", "z", "keepPacked":
+		default:
+			data[k] = v
+		}
+	}
+
+	blockEntityMu.RLock()
+	f, ok := blockEntities[id]
+	blockEntityMu.RUnlock()
+
+	var be BlockEntity = &GenericBlockEntity{Identifier: id}
+	if ok {
+		be = f()
+	}
+	be.DecodeNBT(data)
+	return BlockPos{x, y, z}, be, nil
+}
+
+// GenericBlockEntity is a block entity of any type, holding its data as a raw NBT map. It is used for block
+// entities that do not have a typed implementation.
+type GenericBlockEntity struct {
+	// Identifier is the namespaced identifier of the block entity.
+	Identifier string
+	// Data is the raw NBT data of the block entity.
+	Data map[string]interface{}
+}
+
+// ID ...
+func (g *GenericBlockEntity) ID() string {
+	return g.Identifier
+}
+
+// EncodeNBT ...
+func (g *GenericBlockEntity) EncodeNBT() map[string]interface{} {
+	m := make(map[string]interface{}, len(g.Data))
+	for k, v := range g.Data {
+		m[k] = v
+	}
+	return m
+}
+
+// DecodeNBT ...
+func (g *GenericBlockEntity) DecodeNBT(data map[string]interface{}) {
+	g.Data = data
+}
+
+// Sign is the block entity of both standing and wall signs.
+type Sign struct {
+	// Lines contains the four lines of text on the sign.
+	Lines [4]text.Text
+	// Color is the dye color of the text on the sign, such as "black".
+	Color string
+	// Glowing is true if the text on the sign has been made to glow using a glow ink sac.
+	Glowing bool
+}
+
+// ID ...
+func (*Sign) ID() string {
+	return "minecraft:sign"
+}
+
+// EncodeNBT ...
+func (s *Sign) EncodeNBT() map[string]interface{} {
+	color := s.Color
+	if color == "" {
+		color = "black"
+	}
+	m := map[string]interface{}{
+		"Color":       color,
+		"GlowingText": boolByte(s.Glowing),
+	}
+	for i, line := range s.Lines {
+		m[fmt.Sprintf("Text%v", i+1)] = encodeText(line)
+	}
+	return m
+}
+
+// DecodeNBT ...
+func (s *Sign) DecodeNBT(data map[string]interface{}) {
+	s.Color, _ = data["Color"].(string)
+	s.Glowing = byteBool(data["GlowingText"])
+	for i := range s.Lines {
+		s.Lines[i] = decodeText(data[fmt.Sprintf("Text%v", i+1)])
+	}
+}
+
+// Container is the block entity of blocks which hold items, such as chests, barrels and hoppers.
+type Container struct {
+	// Kind is the namespaced identifier of the container, such as minecraft:chest or minecraft:barrel. If
+	// left empty, the container is a chest.
+	Kind string
+	// CustomName is the custom name of the container, shown at the top of its inventory. It is omitted if nil.
+	CustomName *text.Text
+	// Lock is the name an item must have for a player to be able to open the container.
+	Lock string
+	// Items contains all items in the container.
+	Items []ContainerItem
+}
+
+// ContainerItem is an item stack stored in a slot of a container.
+type ContainerItem struct {
+	// Slot is the slot of the container the item is in.
+	Slot byte
+	// Name is the namespaced identifier of the item, such as minecraft:stone.
+	Name string
+	// Count is the number of items in the stack.
+	Count byte
+	// Tag contains any additional NBT data of the item, such as its display name or enchantments.
+	Tag map[string]interface{}
+}
+
+// ID ...
+func (c *Container) ID() string {
+	if c.Kind == "" {
+		return "minecraft:chest"
+	}
+	return c.Kind
+}
+
+// EncodeNBT ...
+func (c *Container) EncodeNBT() map[string]interface{} {
+	items := make([]interface{}, 0, len(c.Items))
+	for _, item := range c.Items {
+		m := map[string]interface{}{
+			"Slot":  item.Slot,
+			"id":    item.Name,
+			"Count": item.Count,
+		}
+		if len(item.Tag) > 0 {
+			m["tag"] = item.Tag
+		}
+		items = append(items, m)
+	}
+
+	m := map[string]interface{}{"Items": items}
+	if c.CustomName != nil {
+		m["CustomName"] = encodeText(*c.CustomName)
+	}
+	if c.Lock != "" {
+		m["Lock"] = c.Lock
+	}
+	return m
+}
+
+// DecodeNBT ...
+func (c *Container) DecodeNBT(data map[string]interface{}) {
+	if name, ok := data["CustomName"]; ok {
+		t := decodeText(name)
+		c.CustomName = &t
+	}
+	c.Lock, _ = data["Lock"].(string)
+
+	items, _ := data["Items"].([]interface{})
+	c.Items = make([]ContainerItem, 0, len(items))
+	for _, v := range items {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		item := ContainerItem{}
+		item.Slot, _ = m["Slot"].(byte)
+		item.Name, _ = m["id"].(string)
+		item.Count, _ = m["Count"].(byte)
+		item.Tag, _ = m["tag"].(map[string]interface{})
+		c.Items = append(c.Items, item)
+	}
+}
+
+// BannerPattern is a single pattern layer applied on a banner.
+type BannerPattern struct {
+	// Pattern is the short code of the pattern, such as "bs" for a base or "cre" for a creeper charge.
+	Pattern string
+	// Color is the dye color ID of the pattern.
+	Color int32
+}
+
+// Banner is the block entity of both standing and wall banners. The base color of a banner is determined by
+// the block state.
+type Banner struct {
+	// CustomName is the custom name of the banner. It is omitted if nil.
+	CustomName *text.Text
+	// Patterns contains all pattern layers of the banner, from the bottom to the top layer.
+	Patterns []BannerPattern
+}
+
+// ID ...
+func (*Banner) ID() string {
+	return "minecraft:banner"
+}
+
+// EncodeNBT ...
+func (b *Banner) EncodeNBT() map[string]interface{} {
+	patterns := make([]interface{}, 0, len(b.Patterns))
+	for _, pattern := range b.Patterns {
+		patterns = append(patterns, map[string]interface{}{
+			"Pattern": pattern.Pattern,
+			"Color":   pattern.Color,
+		})
+	}
+
+	m := map[string]interface{}{"Patterns": patterns}
+	if b.CustomName != nil {
+		m["CustomName"] = encodeText(*b.CustomName)
+	}
+	return m
+}
+
+// DecodeNBT ...
+func (b *Banner) DecodeNBT(data map[string]interface{}) {
+	if name, ok := data["CustomName"]; ok {
+		t := decodeText(name)
+		b.CustomName = &t
+	}
+
+	patterns, _ := data["Patterns"].([]interface{})
+	b.Patterns = make([]BannerPattern, 0, len(patterns))
+	for _, v := range patterns {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pattern := BannerPattern{}
+		pattern.Pattern, _ = m["Pattern"].(string)
+		pattern.Color, _ = m["Color"].(int32)
+		b.Patterns = append(b.Patterns, pattern)
+	}
+}
+
+// Skull is the block entity of player and mob heads.
+type Skull struct {
+	// OwnerName is the name of the player the skull belongs to. It is only used for player heads.
+	OwnerName string
+	// OwnerID is the UUID of the player the skull belongs to. It is only used for player heads.
+	OwnerID uuid.UUID
+	// Textures is the base64 encoded textures property of the owner, used to render custom skins.
+	Textures string
+}
+
+// ID ...
+func (*Skull) ID() string {
+	return "minecraft:skull"
+}
+
+// EncodeNBT ...
+func (s *Skull) EncodeNBT() map[string]interface{} {
+	if s.OwnerName == "" && s.OwnerID == uuid.Nil && s.Textures == "" {
+		return map[string]interface{}{}
+	}
+
+	owner := map[string]interface{}{"Id": uuidToInt32Array(s.OwnerID)}
+	if s.OwnerName != "" {
+		owner["Name"] = s.OwnerName
+	}
+	if s.Textures != "" {
+		owner["Properties"] = map[string]interface{}{
+			"textures": []interface{}{map[string]interface{}{"Value": s.Textures}},
+		}
+	}
+	return map[string]interface{}{"SkullOwner": owner}
+}
+
+// DecodeNBT ...
+func (s *Skull) DecodeNBT(data map[string]interface{}) {
+	owner, ok := data["SkullOwner"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	s.OwnerName, _ = owner["Name"].(string)
+	if id, ok := owner["Id"].([4]int32); ok {
+		s.OwnerID = int32ArrayToUUID(id)
+	}
+	if properties, ok := owner["Properties"].(map[string]interface{}); ok {
+		if textures, ok := properties["textures"].([]interface{}); ok && len(textures) > 0 {
+			if texture, ok := textures[0].(map[string]interface{}); ok {
+				s.Textures, _ = texture["Value"].(string)
+			}
+		}
+	}
+}
+
+// Bed is the block entity of beds. Its color is determined by the block state, so it holds no data.
+type Bed struct{}
+
+// ID ...
+func (*Bed) ID() string {
+	return "minecraft:bed"
+}
+
+// EncodeNBT ...
+func (*Bed) EncodeNBT() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+// DecodeNBT ...
+func (*Bed) DecodeNBT(map[string]interface{}) {}
+
+// encodeText encodes text to the JSON string representation used in NBT.
+func encodeText(t text.Text) string {
+	b, _ := json.Marshal(t)
+	return string(b)
+}
+
+// decodeText decodes text from the JSON string representation used in NBT. Invalid text results in empty text.
+func decodeText(v interface{}) text.Text {
+	var t text.Text
+	if s, ok := v.(string); ok {
+		_ = json.Unmarshal([]byte(s), &t)
+	}
+	return t
+}
+
+// boolByte converts a bool to a byte, as used for TAG_Byte.
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// byteBool converts a TAG_Byte value to a bool.
+func byteBool(v interface{}) bool {
+	b, _ := v.(byte)
+	return b != 0
+}
+
+// uuidToInt32Array converts a UUID to the int array representation used in NBT.
+func uuidToInt32Array(id uuid.UUID) [4]int32 {
+	var arr [4]int32
+	for i := range arr {
+		arr[i] = int32(id[i*4])<<24 | int32(id[i*4+1])<<16 | int32(id[i*4+2])<<8 | int32(id[i*4+3])
+	}
+	return arr
+}
+
+// int32ArrayToUUID converts the int array representation used in NBT to a UUID.
+func int32ArrayToUUID(arr [4]int32) uuid.UUID {
+	var id uuid.UUID
+	for i, v := range arr {
+		id[i*4], id[i*4+1], id[i*4+2], id[i*4+3] = byte(v>>24), byte(v>>16), byte(v>>8), byte(v)
+	}
+	return id
+}
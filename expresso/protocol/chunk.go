@@ -3,6 +3,10 @@ package protocol
 // This has effectively been ported from Geyser's MCProtocolLib. Thanks a ton!
 // https://github.com/GeyserMC/MCProtocolLib
 
+import (
+	"fmt"
+)
+
 const (
 	// air is the ID of the air block.
 	air = 0
@@ -25,6 +29,8 @@ type Chunk struct {
 	palette Palette
 	// storage contains the bit storage of the chunk.
 	storage *BitStorage
+	// fragmented is true if the palette may contain block states that are no longer used in the chunk.
+	fragmented bool
 }
 
 // NewEmptyChunk creates a new empty chunk.
@@ -63,6 +69,11 @@ func (c *Chunk) SetBlockState(x, y, z, state int32) error {
 	} else if state == air && curr != air {
 		c.blockCount--
 	}
+	if curr != id {
+		// The previous block state may have been the last of its kind, so the palette could now hold an
+		// entry that is never referenced.
+		c.fragmented = true
+	}
 
 	return c.storage.Set(ind, id)
 }
@@ -72,6 +83,42 @@ func (c *Chunk) Empty() bool {
 	return c.blockCount == 0
 }
 
+// Compact rebuilds the palette and storage of the chunk so that they only contain the block states that are
+// actually in use, using the smallest number of bits per entry possible. Air is always held by the palette,
+// so a chunk which only contains a single other block state ends up with a palette holding air and that state.
+// If an error is returned, the chunk is left unchanged.
+func (c *Chunk) Compact() error {
+	used := make(map[int32]struct{})
+	states := make([]int32, chunkSize)
+
+	// Air is always mapped to the first ID of a palette, so we make sure it is accounted for.
+	used[air] = struct{}{}
+	blockCount := int32(0)
+	for i := int32(0); i < chunkSize; i++ {
+		id, _ := c.storage.Get(i)
+		state := c.palette.IDToState(id)
+		if state != air {
+			blockCount++
+		}
+
+		states[i] = state
+		used[state] = struct{}{}
+	}
+
+	bitsPerEntry := sanitizeBitsPerEntry(bitsRequired(int32(len(used))))
+	newPalette := createPalette(bitsPerEntry)
+	newStorage := NewEmptyBitStorage(bitsPerEntry, chunkSize)
+
+	for i, state := range states {
+		if err := newStorage.Set(int32(i), newPalette.StateToID(state)); err != nil {
+			return fmt.Errorf("compact chunk: block state %v does not fit in palette: %w", state, err)
+		}
+	}
+
+	c.palette, c.storage, c.blockCount, c.fragmented = newPalette, newStorage, blockCount, false
+	return nil
+}
+
 // resizePalette resizes the palette of the chunk.
 func (c *Chunk) resizePalette() {
 	bitsPerEntry := sanitizeBitsPerEntry(c.storage.bitsPerEntry + 1)
@@ -86,6 +133,15 @@ func (c *Chunk) resizePalette() {
 	c.palette, c.storage = newPalette, newStorage
 }
 
+// bitsRequired returns the number of bits required to represent the given number of palette entries.
+func bitsRequired(entries int32) int32 {
+	bits := int32(1)
+	for int32(1)<<bits < entries {
+		bits++
+	}
+	return bits
+}
+
 // sanitizeBitsPerEntry sanitizes the bitsPerEntry per entry of the palette.
 func sanitizeBitsPerEntry(bitsPerEntry int32) int32 {
 	if bitsPerEntry <= maximumPaletteBitsPerEntry {
@@ -0,0 +1,42 @@
+package protocol
+
+import (
+	"testing"
+)
+
+// TestChunkCompact tests that compacting a chunk keeps all of its blocks, in particular when the number of
+// distinct block states, including air, is exactly a power of two.
+func TestChunkCompact(t *testing.T) {
+	for _, n := range []int32{2, 16, 17, 32, 33, 64, 128, 256, 257} {
+		c := NewEmptyChunk()
+		expected := make([]int32, chunkSize)
+		// Set a block state that is removed again, so that the chunk is fragmented.
+		if err := c.SetBlockState(0, 0, 0, 10000); err != nil {
+			t.Fatalf("%v states: set block: %v", n, err)
+		}
+		for i := int32(0); i < chunkSize; i++ {
+			x, y, z := i&15, i>>8, (i>>4)&15
+			state := i % n
+			if err := c.SetBlockState(x, y, z, state); err != nil {
+				t.Fatalf("%v states: set block: %v", n, err)
+			}
+			expected[index(x, y, z)] = state
+		}
+		if err := c.Compact(); err != nil {
+			t.Fatalf("%v states: compact: %v", n, err)
+		}
+		for i := int32(0); i < chunkSize; i++ {
+			x, y, z := i&15, i>>8, (i>>4)&15
+			state, err := c.GetBlockState(x, y, z)
+			if err != nil {
+				t.Fatalf("%v states: get block: %v", n, err)
+			}
+			if state != expected[index(x, y, z)] {
+				t.Fatalf("%v states: block at %v %v %v: expected %v, got %v", n, x, y, z, expected[index(x, y, z)], state)
+			}
+		}
+		if n <= 256 && c.palette.Size() != n {
+			t.Errorf("%v states: expected palette of %v states, got %v", n, n, c.palette.Size())
+		}
+	}
+}
@@ -1,41 +1,101 @@
 package protocol
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
 // Column represents a chunk column, which contains chunk data, the chunk position, biomes,
 // and other useful information for the client.
 type Column struct {
 	// Position is the position of the column.
 	Position ColumnPos
-	// Chunks contain all chunks associated with the column.
+	// MinY is the lowest Y coordinate of the column. It is taken from the min_y of the dimension type the column
+	// is in, and is always a multiple of sixteen.
+	MinY int32
+	// Sections is the number of chunk sections in the column. It is taken from the height of the dimension type
+	// the column is in, divided by sixteen.
+	Sections int32
+	// Chunks contain all chunks associated with the column, keyed by their index counting up from the lowest
+	// chunk section of the column.
 	Chunks map[int32]*Chunk
-	// Tiles contains all tile entities associated with the column.
-	Tiles []map[string]interface{}
+	// BlockEntities contains all block entities in the column, keyed by their position relative to the column.
+	BlockEntities map[BlockPos]BlockEntity
 	// HeightMaps contains all height maps associated with the column.
 	HeightMaps map[string]interface{}
 	// Biomes contains all biomes associated with the column.
 	Biomes []int32
+
+	// changes holds all block changes per chunk index since the changes were last flushed. It is nil if
+	// changes are not being tracked.
+	changes map[int32]map[BlockPos]*BlockChange
+
+	// mu is locked using Lock and Unlock while the column is read or modified by multiple goroutines.
+	mu sync.Mutex
+}
+
+// BlockChange is a change of a block in a column.
+type BlockChange struct {
+	// Position is the position of the block relative to the column.
+	Position BlockPos
+	// State is the new block state of the block.
+	State int32
+
+	// original is the block state the block had before the first change since the last flush.
+	original int32
 }
 
-// NewColumn initializes a new empty chunk column.
+// NewColumn initializes a new empty chunk column, ranging from Y zero to Y 255.
 func NewColumn(pos ColumnPos) *Column {
-	defaultBiomes := make([]int32, 1024)
-	for i := 0; i < 1024; i++ {
+	return NewColumnWithHeight(pos, 0, 256)
+}
+
+// NewColumnWithHeight initializes a new empty chunk column using the min_y and height of a dimension type.
+// Both the minimum Y and the height must be multiples of sixteen.
+func NewColumnWithHeight(pos ColumnPos, minY, height int32) *Column {
+	// Biomes are stored in cells of four by four by four blocks.
+	defaultBiomes := make([]int32, height*4)
+	for i := range defaultBiomes {
 		defaultBiomes[i] = 1
 	}
 
 	return &Column{
-		Position:   pos,
-		Chunks:     make(map[int32]*Chunk),
-		Tiles:      make([]map[string]interface{}, 0),
-		HeightMaps: make(map[string]interface{}),
-		Biomes:     defaultBiomes,
+		Position:      pos,
+		MinY:          minY,
+		Sections:      height >> 4,
+		Chunks:        make(map[int32]*Chunk),
+		HeightMaps:    make(map[string]interface{}),
+		Biomes:        defaultBiomes,
+		BlockEntities: make(map[BlockPos]BlockEntity),
 	}
 }
 
+// Lock locks the column, so that it may be read or modified while it is shared between goroutines, such as
+// when it is sent to multiple connections. The methods of Column do not lock the column themselves, but
+// packet.ChunkData and packet.BlockChanges do, as writing chunks compacts them. They must therefore not be used
+// while the lock is held.
+func (c *Column) Lock() {
+	c.mu.Lock()
+}
+
+// Unlock unlocks the column after a call to Lock.
+func (c *Column) Unlock() {
+	c.mu.Unlock()
+}
+
+// MaxY returns the highest Y coordinate of the column.
+func (c *Column) MaxY() int32 {
+	return c.MinY + c.Sections<<4 - 1
+}
+
+// ChunkIndex returns the index of the chunk that the Y coordinate passed is in.
+func (c *Column) ChunkIndex(y int32) int32 {
+	return (y - c.MinY) >> 4
+}
+
 // GetBlockState returns the state ID of a block position.
 func (c *Column) GetBlockState(pos BlockPos) (int32, error) {
-	chunk := c.Chunks[pos.Y()>>4]
+	chunk := c.Chunks[c.ChunkIndex(pos.Y())]
 	if chunk == nil || chunk.Empty() {
 		// The chunk is empty or does not exist, so the block is air.
 		return 0, nil
@@ -47,9 +107,14 @@ func (c *Column) GetBlockState(pos BlockPos) (int32, error) {
 
 // SetBlockState sets the state ID of a block position.
 func (c *Column) SetBlockState(pos BlockPos, state int32) error {
-	chunkIndex := pos.Y() >> 4
-	if chunkIndex < 0 || chunkIndex >= 16 {
-		return fmt.Errorf("invalid chunk index")
+	chunkIndex := c.ChunkIndex(pos.Y())
+	if chunkIndex < 0 || chunkIndex >= c.Sections {
+		return fmt.Errorf("invalid chunk index: y %v is outside the range %v-%v", pos.Y(), c.MinY, c.MaxY())
+	}
+	if c.changes != nil {
+		if err := c.trackChange(chunkIndex, pos, state); err != nil {
+			return err
+		}
 	}
 
 	chunk, ok := c.Chunks[chunkIndex]
@@ -67,3 +132,94 @@ func (c *Column) SetBlockState(pos BlockPos, state int32) error {
 
 	return chunk.SetBlockState(pos.X(), pos.Y()&15, pos.Z(), state)
 }
+
+// TrackChanges starts tracking all changes made using SetBlockState, so that they may be retrieved using
+// FlushChanges. Calling TrackChanges when changes are already tracked has no effect.
+func (c *Column) TrackChanges() {
+	if c.changes == nil {
+		c.changes = make(map[int32]map[BlockPos]*BlockChange)
+	}
+}
+
+// FlushChanges returns all blocks that changed since changes were last flushed, grouped by chunk index. Blocks
+// that were changed back to their original state are left out. The tracked changes are reset afterwards.
+func (c *Column) FlushChanges() map[int32][]BlockChange {
+	flushed := make(map[int32][]BlockChange)
+	for chunkIndex, changes := range c.changes {
+		for _, change := range changes {
+			if change.State != change.original {
+				flushed[chunkIndex] = append(flushed[chunkIndex], *change)
+			}
+		}
+		delete(c.changes, chunkIndex)
+	}
+	return flushed
+}
+
+// trackChange records the change of the block at the position passed to the state passed.
+func (c *Column) trackChange(chunkIndex int32, pos BlockPos, state int32) error {
+	changes, ok := c.changes[chunkIndex]
+	if !ok {
+		changes = make(map[BlockPos]*BlockChange)
+		c.changes[chunkIndex] = changes
+	}
+	if change, ok := changes[pos]; ok {
+		change.State = state
+		return nil
+	}
+
+	original, err := c.GetBlockState(pos)
+	if err != nil {
+		return err
+	}
+	changes[pos] = &BlockChange{Position: pos, State: state, original: original}
+	return nil
+}
+
+// BlockEntity returns the block entity at the position passed, relative to the column. If no block entity
+// exists at the position, false is returned.
+func (c *Column) BlockEntity(pos BlockPos) (BlockEntity, bool) {
+	be, ok := c.BlockEntities[pos]
+	return be, ok
+}
+
+// SetBlockEntity sets the block entity at the position passed, relative to the column. An error is returned if
+// the position is outside the column.
+func (c *Column) SetBlockEntity(pos BlockPos, be BlockEntity) error {
+	if pos.X() < 0 || pos.X() >= 16 || pos.Z() < 0 || pos.Z() >= 16 || pos.Y() < c.MinY || pos.Y() > c.MaxY() {
+		return fmt.Errorf("block entity position %v is outside the column", pos)
+	}
+	if be.ID() == "" {
+		return fmt.Errorf("block entity at %v has no identifier", pos)
+	}
+	c.BlockEntities[pos] = be
+	return nil
+}
+
+// RemoveBlockEntity removes the block entity at the position passed, relative to the column, if one exists.
+func (c *Column) RemoveBlockEntity(pos BlockPos) {
+	delete(c.BlockEntities, pos)
+}
+
+// EncodeBlockEntities encodes all block entities in the column to their full NBT representation, with the
+// positions converted to world positions.
+func (c *Column) EncodeBlockEntities() []map[string]interface{} {
+	blockEntities := make([]map[string]interface{}, 0, len(c.BlockEntities))
+	for pos, be := range c.BlockEntities {
+		blockEntities = append(blockEntities, EncodeBlockEntity(c.Position.WorldPos(pos), be))
+	}
+	return blockEntities
+}
+
+// DecodeBlockEntities decodes the full NBT representation of block entities passed and adds them to the
+// column, with the world positions converted to positions relative to the column.
+func (c *Column) DecodeBlockEntities(blockEntities []map[string]interface{}) error {
+	for _, m := range blockEntities {
+		pos, be, err := DecodeBlockEntity(m)
+		if err != nil {
+			return err
+		}
+		c.BlockEntities[BlockPos{pos.X() & 15, pos.Y(), pos.Z() & 15}] = be
+	}
+	return nil
+}
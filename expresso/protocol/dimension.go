@@ -0,0 +1,259 @@
+package protocol
+
+import (
+	_ "embed"
+	"fmt"
+	"github.com/justtaldevelops/expresso/expresso/nbt"
+)
+
+// DimensionCodec holds the registries of all dimension types and biomes that are sent to the client when it
+// joins the game. Dimension types and biomes may only be used if they are present in the codec.
+type DimensionCodec struct {
+	// DimensionTypes is the registry of all dimension types.
+	DimensionTypes DimensionTypeRegistry `nbt:"minecraft:dimension_type"`
+	// Biomes is the registry of all biomes.
+	Biomes BiomeRegistry `nbt:"minecraft:worldgen/biome"`
+}
+
+// DimensionTypeRegistry is the registry of dimension types in the dimension codec.
+type DimensionTypeRegistry struct {
+	// Type is the identifier of the registry, which is always minecraft:dimension_type.
+	Type string `nbt:"type"`
+	// Value contains all entries of the registry.
+	Value []DimensionTypeEntry `nbt:"value"`
+}
+
+// DimensionTypeEntry is a single dimension type in the dimension type registry.
+type DimensionTypeEntry struct {
+	// Name is the namespaced identifier of the dimension type, such as minecraft:overworld.
+	Name string `nbt:"name"`
+	// ID is the numeric ID of the dimension type.
+	ID int32 `nbt:"id"`
+	// Element is the dimension type itself.
+	Element DimensionType `nbt:"element"`
+}
+
+// DimensionType defines the properties of a dimension, such as its height, lighting and what blocks work in it.
+type DimensionType struct {
+	// PiglinSafe is true if piglins do not turn into zombified piglins in the dimension.
+	PiglinSafe bool `nbt:"piglin_safe"`
+	// Natural is true if compasses and clocks work normally, and sleeping in beds is possible.
+	Natural bool `nbt:"natural"`
+	// AmbientLight is the minimum amount of light in the dimension, from zero to one.
+	AmbientLight float32 `nbt:"ambient_light"`
+	// FixedTime is the time of day that is always displayed in the dimension. If nil, time passes normally.
+	FixedTime *int64 `nbt:"fixed_time,omitempty"`
+	// Infiniburn is the tag of blocks on which fire burns forever, such as minecraft:infiniburn_overworld.
+	Infiniburn string `nbt:"infiniburn"`
+	// RespawnAnchorWorks is true if respawn anchors may be used in the dimension.
+	RespawnAnchorWorks bool `nbt:"respawn_anchor_works"`
+	// HasSkylight is true if the dimension has skylight.
+	HasSkylight bool `nbt:"has_skylight"`
+	// BedWorks is true if players may sleep in beds in the dimension.
+	BedWorks bool `nbt:"bed_works"`
+	// Effects determines the sky and fog rendering of the dimension, such as minecraft:the_nether.
+	Effects string `nbt:"effects"`
+	// HasRaids is true if raids can happen in the dimension.
+	HasRaids bool `nbt:"has_raids"`
+	// MinY is the lowest Y coordinate of the dimension. It must be a multiple of sixteen.
+	MinY int32 `nbt:"min_y"`
+	// Height is the number of blocks high the dimension is. It must be a multiple of sixteen.
+	Height int32 `nbt:"height"`
+	// LogicalHeight is the maximum height to which portals can teleport players and chorus fruit can be used.
+	LogicalHeight int32 `nbt:"logical_height"`
+	// CoordinateScale is the scale of coordinates when travelling through a nether portal into the dimension.
+	CoordinateScale float64 `nbt:"coordinate_scale"`
+	// Ultrawarm is true if water evaporates and lava flows faster in the dimension.
+	Ultrawarm bool `nbt:"ultrawarm"`
+	// HasCeiling is true if the dimension has a bedrock ceiling.
+	HasCeiling bool `nbt:"has_ceiling"`
+}
+
+// BiomeRegistry is the registry of biomes in the dimension codec.
+type BiomeRegistry struct {
+	// Type is the identifier of the registry, which is always minecraft:worldgen/biome.
+	Type string `nbt:"type"`
+	// Value contains all entries of the registry.
+	Value []BiomeEntry `nbt:"value"`
+}
+
+// BiomeEntry is a single biome in the biome registry.
+type BiomeEntry struct {
+	// Name is the namespaced identifier of the biome, such as minecraft:plains.
+	Name string `nbt:"name"`
+	// ID is the numeric ID of the biome, which is used in the biomes of columns.
+	ID int32 `nbt:"id"`
+	// Element is the biome itself.
+	Element Biome `nbt:"element"`
+}
+
+// Biome defines the properties of a biome that are relevant to the client, such as its colours and sounds.
+type Biome struct {
+	// Precipitation is the type of precipitation in the biome: rain, snow or none.
+	Precipitation string `nbt:"precipitation"`
+	// Depth is the depth of the terrain in the biome, which is used for the fog.
+	Depth float32 `nbt:"depth"`
+	// Temperature is the temperature of the biome, which affects grass and foliage colours.
+	Temperature float32 `nbt:"temperature"`
+	// TemperatureModifier modifies the temperature of the biome, such as frozen. It is omitted if empty.
+	TemperatureModifier string `nbt:"temperature_modifier,omitempty"`
+	// Scale is the scale of the terrain in the biome.
+	Scale float32 `nbt:"scale"`
+	// Downfall is the amount of downfall in the biome, which affects grass and foliage colours.
+	Downfall float32 `nbt:"downfall"`
+	// Category is the category of the biome, such as plains or ocean.
+	Category string `nbt:"category"`
+	// Effects holds the visual and audible effects of the biome.
+	Effects BiomeEffects `nbt:"effects"`
+}
+
+// BiomeEffects holds the colours, particles and sounds of a biome.
+type BiomeEffects struct {
+	// SkyColour is the colour of the sky in the biome.
+	SkyColour int32 `nbt:"sky_color"`
+	// WaterFogColour is the colour of the fog under water in the biome.
+	WaterFogColour int32 `nbt:"water_fog_color"`
+	// FogColour is the colour of the fog in the biome.
+	FogColour int32 `nbt:"fog_color"`
+	// WaterColour is the colour of water in the biome.
+	WaterColour int32 `nbt:"water_color"`
+	// FoliageColour overrides the colour of leaves in the biome. If nil, it is based on the temperature.
+	FoliageColour *int32 `nbt:"foliage_color,omitempty"`
+	// GrassColour overrides the colour of grass in the biome. If nil, it is based on the temperature.
+	GrassColour *int32 `nbt:"grass_color,omitempty"`
+	// GrassColourModifier modifies the colour of grass, such as swamp or dark_forest. It is omitted if empty.
+	GrassColourModifier string `nbt:"grass_color_modifier,omitempty"`
+	// Particle is the ambient particle of the biome. It is omitted if nil.
+	Particle *BiomeParticle `nbt:"particle,omitempty"`
+	// AmbientSound is the sound that loops in the biome. It is omitted if empty.
+	AmbientSound string `nbt:"ambient_sound,omitempty"`
+	// MoodSound is the sound played in dark places of the biome. It is omitted if nil.
+	MoodSound *BiomeMoodSound `nbt:"mood_sound,omitempty"`
+	// AdditionsSound is a sound played randomly in the biome. It is omitted if nil.
+	AdditionsSound *BiomeAdditionsSound `nbt:"additions_sound,omitempty"`
+	// Music is the music played in the biome. It is omitted if nil.
+	Music *BiomeMusic `nbt:"music,omitempty"`
+}
+
+// BiomeParticle is an ambient particle of a biome.
+type BiomeParticle struct {
+	// Probability is the chance of the particle spawning, from zero to one.
+	Probability float32 `nbt:"probability"`
+	// Options holds the particle type.
+	Options struct {
+		// Type is the identifier of the particle, such as minecraft:ash.
+		Type string `nbt:"type"`
+	} `nbt:"options"`
+}
+
+// BiomeMoodSound is a sound played in dark places of a biome.
+type BiomeMoodSound struct {
+	// Sound is the identifier of the sound.
+	Sound string `nbt:"sound"`
+	// TickDelay is the minimum delay between two plays of the sound, in ticks.
+	TickDelay int32 `nbt:"tick_delay"`
+	// BlockSearchExtent is the radius in which the darkness is checked.
+	BlockSearchExtent int32 `nbt:"block_search_extent"`
+	// Offset is the distance offset of the sound from the player.
+	Offset float64 `nbt:"offset"`
+}
+
+// BiomeAdditionsSound is a sound played randomly in a biome.
+type BiomeAdditionsSound struct {
+	// Sound is the identifier of the sound.
+	Sound string `nbt:"sound"`
+	// TickChance is the chance of the sound being played each tick.
+	TickChance float64 `nbt:"tick_chance"`
+}
+
+// BiomeMusic is the music played in a biome.
+type BiomeMusic struct {
+	// Sound is the identifier of the music.
+	Sound string `nbt:"sound"`
+	// MinDelay is the minimum delay between two songs, in ticks.
+	MinDelay int32 `nbt:"min_delay"`
+	// MaxDelay is the maximum delay between two songs, in ticks.
+	MaxDelay int32 `nbt:"max_delay"`
+	// ReplaceCurrentMusic is true if the music replaces any music that is already playing.
+	ReplaceCurrentMusic bool `nbt:"replace_current_music"`
+}
+
+//go:embed dimension_codec.nbt
+var dimensionCodecData []byte
+
+// DefaultDimensionCodec returns a new dimension codec holding all vanilla dimension types and biomes. The codec
+// returned may be modified freely.
+func DefaultDimensionCodec() DimensionCodec {
+	var codec DimensionCodec
+	if err := nbt.Unmarshal(dimensionCodecData, &codec); err != nil {
+		panic(fmt.Errorf("decode default dimension codec: %w", err))
+	}
+	return codec
+}
+
+// DefaultDimensionType returns the vanilla overworld dimension type, ranging from Y zero to Y 255.
+func DefaultDimensionType() DimensionType {
+	codec := DefaultDimensionCodec()
+	d, _ := codec.DimensionType("minecraft:overworld")
+	return d
+}
+
+// DimensionType returns the dimension type with the name passed. False is returned if the codec does not have
+// a dimension type with the name.
+func (c *DimensionCodec) DimensionType(name string) (DimensionType, bool) {
+	for _, entry := range c.DimensionTypes.Value {
+		if entry.Name == name {
+			return entry.Element, true
+		}
+	}
+	return DimensionType{}, false
+}
+
+// SetDimensionType adds the dimension type passed to the codec with the name passed. If the codec already
+// has a dimension type with the name, it is replaced.
+func (c *DimensionCodec) SetDimensionType(name string, d DimensionType) {
+	nextID := int32(0)
+	for i, entry := range c.DimensionTypes.Value {
+		if entry.Name == name {
+			c.DimensionTypes.Value[i].Element = d
+			return
+		}
+		if entry.ID >= nextID {
+			nextID = entry.ID + 1
+		}
+	}
+	c.DimensionTypes.Value = append(c.DimensionTypes.Value, DimensionTypeEntry{Name: name, ID: nextID, Element: d})
+}
+
+// Biome returns the biome with the name passed and its ID. False is returned if the codec does not have a
+// biome with the name.
+func (c *DimensionCodec) Biome(name string) (Biome, int32, bool) {
+	for _, entry := range c.Biomes.Value {
+		if entry.Name == name {
+			return entry.Element, entry.ID, true
+		}
+	}
+	return Biome{}, 0, false
+}
+
+// SetBiome adds the biome passed to the codec with the name passed and returns its ID. If the codec already
+// has a biome with the name, it is replaced and keeps its ID.
+func (c *DimensionCodec) SetBiome(name string, b Biome) int32 {
+	nextID := int32(0)
+	for i, entry := range c.Biomes.Value {
+		if entry.Name == name {
+			c.Biomes.Value[i].Element = b
+			return entry.ID
+		}
+		if entry.ID >= nextID {
+			nextID = entry.ID + 1
+		}
+	}
+	c.Biomes.Value = append(c.Biomes.Value, BiomeEntry{Name: name, ID: nextID, Element: b})
+	return nextID
+}
+
+// NewColumn initializes a new empty chunk column with the minimum Y and height of the dimension type.
+func (d DimensionType) NewColumn(pos ColumnPos) *Column {
+	return NewColumnWithHeight(pos, d.MinY, d.Height)
+}
@@ -37,13 +37,16 @@ type IO interface {
 
 	// UUID reads/writes a UUID from/to the underlying buffer.
 	UUID(x *uuid.UUID)
+	// BlockPos reads/writes a block position, packed into an int64, from/to the underlying buffer.
+	BlockPos(x *BlockPos)
 	// Text reads/writes Minecraft-style text from/to the underlying buffer.
 	Text(x *text.Text)
 	// Chunk reads/writes a chunk from/to the underlying buffer.
 	Chunk(x *Chunk)
 
-	// NBT reads/writes a map as a compound tag from/to the underlying buffer.
-	NBT(x *map[string]interface{})
+	// NBT reads/writes a map or struct as a compound tag from/to the underlying buffer. When reading, x must be
+	// a pointer.
+	NBT(x interface{})
 }
 
 // Compile time checks to make sure IO is implemented by Writer and Reader.
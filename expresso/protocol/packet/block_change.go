@@ -0,0 +1,28 @@
+package packet
+
+import "github.com/justtaldevelops/expresso/expresso/protocol"
+
+// BlockChange is sent by the server to change a single block client-side.
+type BlockChange struct {
+	// Position is the world position of the block.
+	Position protocol.BlockPos
+	// State is the new block state of the block.
+	State int32
+}
+
+// ID ...
+func (*BlockChange) ID() int32 {
+	return 0x0C
+}
+
+// Marshal ...
+func (pk *BlockChange) Marshal(w *protocol.Writer) {
+	w.BlockPos(&pk.Position)
+	w.Varint32(&pk.State)
+}
+
+// Unmarshal ...
+func (pk *BlockChange) Unmarshal(r *protocol.Reader) {
+	r.BlockPos(&pk.Position)
+	r.Varint32(&pk.State)
+}
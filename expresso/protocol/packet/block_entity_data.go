@@ -0,0 +1,43 @@
+package packet
+
+import "github.com/justtaldevelops/expresso/expresso/protocol"
+
+// BlockEntityData is sent by the server to update a single block entity client-side, for example after the
+// text on a sign has changed, without resending the chunk it is in.
+type BlockEntityData struct {
+	// Position is the world position of the block entity.
+	Position protocol.BlockPos
+	// BlockEntity is the block entity being updated. The action of the packet is derived from its identifier.
+	BlockEntity protocol.BlockEntity
+}
+
+// ID ...
+func (*BlockEntityData) ID() int32 {
+	return 0x0A
+}
+
+// Marshal ...
+func (pk *BlockEntityData) Marshal(w *protocol.Writer) {
+	action := protocol.BlockEntityAction(pk.BlockEntity)
+	data := protocol.EncodeBlockEntity(pk.Position, pk.BlockEntity)
+
+	w.BlockPos(&pk.Position)
+	w.Uint8(&action)
+	w.NBT(&data)
+}
+
+// Unmarshal ...
+func (pk *BlockEntityData) Unmarshal(r *protocol.Reader) {
+	var action byte
+	var data map[string]interface{}
+
+	r.BlockPos(&pk.Position)
+	r.Uint8(&action)
+	r.NBT(&data)
+
+	_, blockEntity, err := protocol.DecodeBlockEntity(data)
+	if err != nil {
+		panic(err)
+	}
+	pk.BlockEntity = blockEntity
+}
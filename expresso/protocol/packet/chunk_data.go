@@ -8,7 +8,9 @@ import (
 
 // ChunkData is sent by the server to update a chunk client-side
 type ChunkData struct {
-	// Column is the chunk column that is being referenced.
+	// Column is the chunk column that is being referenced. Its minimum Y and number of sections must match the
+	// dimension type the client is in. When decoding a column of a dimension other than the default overworld,
+	// Column should be set to an empty column with the matching height before calling Unmarshal.
 	Column *protocol.Column
 }
 
@@ -19,14 +21,20 @@ func (*ChunkData) ID() int32 {
 
 // Marshal ...
 func (pk *ChunkData) Marshal(w *protocol.Writer) {
+	// Writing the chunks compacts them, so the column is locked in case it is sent to multiple connections.
+	pk.Column.Lock()
+	defer pk.Column.Unlock()
+
 	// Bit set and chunk writing.
 	dataBuffer := &bytes.Buffer{}
 	dataWriter := protocol.NewWriter(dataBuffer)
 
 	bitSet := &bitset.BitSet{}
 
-	for index, chunk := range pk.Column.Chunks {
-		if !chunk.Empty() {
+	// Chunks must be written from the bottom of the column up, in the same order as the bits in the mask.
+	for index := int32(0); index < pk.Column.Sections; index++ {
+		chunk, ok := pk.Column.Chunks[index]
+		if ok && !chunk.Empty() {
 			bitSet.Set(uint(index))
 			dataWriter.Chunk(chunk)
 		}
@@ -62,17 +70,22 @@ func (pk *ChunkData) Marshal(w *protocol.Writer) {
 	dataBytes := dataBuffer.Bytes()
 	w.ByteSlice(&dataBytes)
 
-	// Tile entities.
-	tileEntitiesSize := int32(len(pk.Column.Tiles))
-	w.Varint32(&tileEntitiesSize)
+	// Block entities.
+	blockEntities := pk.Column.EncodeBlockEntities()
+	blockEntitiesSize := int32(len(blockEntities))
+	w.Varint32(&blockEntitiesSize)
 
-	for _, tileEntity := range pk.Column.Tiles {
-		w.NBT(&tileEntity)
+	for _, blockEntity := range blockEntities {
+		w.NBT(&blockEntity)
 	}
 }
 
 // Unmarshal ...
 func (pk *ChunkData) Unmarshal(r *protocol.Reader) {
+	if pk.Column == nil {
+		pk.Column = protocol.NewColumn(protocol.ColumnPos{})
+	}
+
 	// Chunk position.
 	r.Int32(&pk.Column.Position[0])
 	r.Int32(&pk.Column.Position[1])
@@ -108,21 +121,22 @@ func (pk *ChunkData) Unmarshal(r *protocol.Reader) {
 	r.ByteSlice(&data)
 
 	dataReader := protocol.NewReader(bytes.NewReader(data))
-	for index := uint(0); index < chunkMask.Count(); index++ {
-		if chunkMask.Test(index) {
-			var chunk *protocol.Chunk
-			dataReader.Chunk(chunk)
+	for index, ok := chunkMask.NextSet(0); ok; index, ok = chunkMask.NextSet(index + 1) {
+		chunk := &protocol.Chunk{}
+		dataReader.Chunk(chunk)
 
-			pk.Column.Chunks[int32(index)] = chunk
-		}
+		pk.Column.Chunks[int32(index)] = chunk
 	}
 
-	// Tile entities.
-	var tileEntitiesSize int32
-	r.Varint32(&tileEntitiesSize)
+	// Block entities.
+	var blockEntitiesSize int32
+	r.Varint32(&blockEntitiesSize)
 
-	pk.Column.Tiles = make([]map[string]interface{}, tileEntitiesSize)
-	for i := 0; i < int(tileEntitiesSize); i++ {
-		r.NBT(&pk.Column.Tiles[i])
+	blockEntities := make([]map[string]interface{}, blockEntitiesSize)
+	for i := 0; i < int(blockEntitiesSize); i++ {
+		r.NBT(&blockEntities[i])
+	}
+	if err := pk.Column.DecodeBlockEntities(blockEntities); err != nil {
+		panic(err)
 	}
 }
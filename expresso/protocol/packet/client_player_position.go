@@ -0,0 +1,32 @@
+package packet
+
+import "github.com/justtaldevelops/expresso/expresso/protocol"
+
+// ClientPlayerPosition is sent by the client to update the player's position on the server.
+type ClientPlayerPosition struct {
+	// X, Y, Z are the new coordinates of the player. Y is the position of the player's feet.
+	X, Y, Z float64
+	// OnGround is true if the player is on the ground.
+	OnGround bool
+}
+
+// ID ...
+func (*ClientPlayerPosition) ID() int32 {
+	return 0x11
+}
+
+// Marshal ...
+func (pk *ClientPlayerPosition) Marshal(w *protocol.Writer) {
+	w.Float64(&pk.X)
+	w.Float64(&pk.Y)
+	w.Float64(&pk.Z)
+	w.Bool(&pk.OnGround)
+}
+
+// Unmarshal ...
+func (pk *ClientPlayerPosition) Unmarshal(r *protocol.Reader) {
+	r.Float64(&pk.X)
+	r.Float64(&pk.Y)
+	r.Float64(&pk.Z)
+	r.Bool(&pk.OnGround)
+}
@@ -0,0 +1,42 @@
+package packet
+
+import "github.com/justtaldevelops/expresso/expresso/protocol"
+
+// ClientPlayerPositionRotation is sent by the client to update the player's position and rotation on the server.
+type ClientPlayerPositionRotation struct {
+	// X, Y, Z are the new coordinates of the player. Y is the position of the player's feet.
+	X, Y, Z float64
+	// Yaw, Pitch are the new rotation of the player.
+	Yaw, Pitch float32
+	// OnGround is true if the player is on the ground.
+	OnGround bool
+}
+
+// ID ...
+func (*ClientPlayerPositionRotation) ID() int32 {
+	return 0x12
+}
+
+// Marshal ...
+func (pk *ClientPlayerPositionRotation) Marshal(w *protocol.Writer) {
+	w.Float64(&pk.X)
+	w.Float64(&pk.Y)
+	w.Float64(&pk.Z)
+
+	w.Float32(&pk.Yaw)
+	w.Float32(&pk.Pitch)
+
+	w.Bool(&pk.OnGround)
+}
+
+// Unmarshal ...
+func (pk *ClientPlayerPositionRotation) Unmarshal(r *protocol.Reader) {
+	r.Float64(&pk.X)
+	r.Float64(&pk.Y)
+	r.Float64(&pk.Z)
+
+	r.Float32(&pk.Yaw)
+	r.Float32(&pk.Pitch)
+
+	r.Bool(&pk.OnGround)
+}
@@ -0,0 +1,48 @@
+package packet
+
+import "github.com/justtaldevelops/expresso/expresso/protocol"
+
+// ClientSettings is sent by the client when it joins the game, and every time its settings are changed.
+type ClientSettings struct {
+	// Locale is the language of the client, such as en_us.
+	Locale string
+	// ViewDistance is the render distance the client has set, in chunks.
+	ViewDistance byte
+	// ChatMode is zero if chat is enabled, one if only commands are shown, and two if chat is hidden.
+	ChatMode int32
+	// ChatColours is true if the client shows colours in chat.
+	ChatColours bool
+	// DisplayedSkinParts is a bitfield of the skin layers that the client has enabled.
+	DisplayedSkinParts byte
+	// MainHand is zero if the main hand of the player is the left hand, and one if it is the right hand.
+	MainHand int32
+	// DisableTextFiltering is true if the client has disabled text filtering on signs and written books.
+	DisableTextFiltering bool
+}
+
+// ID ...
+func (*ClientSettings) ID() int32 {
+	return 0x05
+}
+
+// Marshal ...
+func (pk *ClientSettings) Marshal(w *protocol.Writer) {
+	w.String(&pk.Locale)
+	w.Uint8(&pk.ViewDistance)
+	w.Varint32(&pk.ChatMode)
+	w.Bool(&pk.ChatColours)
+	w.Uint8(&pk.DisplayedSkinParts)
+	w.Varint32(&pk.MainHand)
+	w.Bool(&pk.DisableTextFiltering)
+}
+
+// Unmarshal ...
+func (pk *ClientSettings) Unmarshal(r *protocol.Reader) {
+	r.String(&pk.Locale)
+	r.Uint8(&pk.ViewDistance)
+	r.Varint32(&pk.ChatMode)
+	r.Bool(&pk.ChatColours)
+	r.Uint8(&pk.DisplayedSkinParts)
+	r.Varint32(&pk.MainHand)
+	r.Bool(&pk.DisableTextFiltering)
+}
@@ -44,15 +44,23 @@ var (
 	playCollection = &collection{
 		// TODO: Add all play packets.
 		clientBoundPackets: map[int32]func() Packet{
+			0x0A: func() Packet { return &BlockEntityData{} },
+			0x0C: func() Packet { return &BlockChange{} },
 			0x1A: func() Packet { return &Disconnect{} },
+			0x1D: func() Packet { return &UnloadChunk{} },
 			0x21: func() Packet { return &ServerKeepAlive{} },
 			0x22: func() Packet { return &ChunkData{} },
 			0x26: func() Packet { return &JoinGame{} },
 			0x38: func() Packet { return &ServerPlayerPositionRotation{} },
+			0x3D: func() Packet { return &Respawn{} },
+			0x3F: func() Packet { return &MultiBlockChange{} },
 			0x49: func() Packet { return &UpdateViewPosition{} },
 		},
 		serverBoundPackets: map[int32]func() Packet{
+			0x05: func() Packet { return &ClientSettings{} },
 			0x0F: func() Packet { return &ClientKeepAlive{} },
+			0x11: func() Packet { return &ClientPlayerPosition{} },
+			0x12: func() Packet { return &ClientPlayerPositionRotation{} },
 		},
 	}
 )
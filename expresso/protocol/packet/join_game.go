@@ -4,6 +4,15 @@ import (
 	"github.com/justtaldevelops/expresso/expresso/protocol"
 )
 
+var (
+	// defaultDimensionCodec is the vanilla dimension codec, which is sent if a JoinGame packet has no dimension
+	// codec. It is decoded once, as it is only read when it is sent.
+	defaultDimensionCodec = protocol.DefaultDimensionCodec()
+	// defaultDimensionType is the vanilla overworld dimension type, which is sent if a JoinGame or Respawn
+	// packet has no dimension type.
+	defaultDimensionType = protocol.DefaultDimensionType()
+)
+
 // JoinGame is sent by the server to the client to join a game.
 type JoinGame struct {
 	// EntityID is the ID of the player joining the game.
@@ -16,6 +25,13 @@ type JoinGame struct {
 	PreviousGameMode byte
 	// Worlds contains all worlds on the server.
 	Worlds []string
+	// DimensionCodec holds all dimension types and biomes that may be used during the game. If nil, the
+	// default vanilla dimension codec is used.
+	DimensionCodec *protocol.DimensionCodec
+	// Dimension is the dimension type of the world the player is joining. If nil, the default overworld
+	// dimension type is used. The minimum Y and height of the dimension type determine the height of the
+	// columns sent to the player, which may be created using DimensionType.NewColumn.
+	Dimension *protocol.DimensionType
 	// World is the name of the world the player is joining.
 	World string
 	// HashedSeed contains the first eight bytes of the world seed in an SHA-256 hash.
@@ -52,8 +68,16 @@ func (pk *JoinGame) Marshal(w *protocol.Writer) {
 		w.String(&world)
 	}
 
-	w.NBT(&dimensionCodec)
-	w.NBT(&dimension)
+	codec, dim := pk.DimensionCodec, pk.Dimension
+	if codec == nil {
+		codec = &defaultDimensionCodec
+	}
+	if dim == nil {
+		dim = &defaultDimensionType
+	}
+
+	w.NBT(codec)
+	w.NBT(dim)
 	w.String(&pk.World)
 	w.Int64(&pk.HashedSeed)
 	w.Varint32(&pk.MaxPlayers)
@@ -79,8 +103,9 @@ func (pk *JoinGame) Unmarshal(r *protocol.Reader) {
 		r.String(&pk.Worlds[i])
 	}
 
-	r.NBT(&dimensionCodec)
-	r.NBT(&dimension)
+	pk.DimensionCodec, pk.Dimension = &protocol.DimensionCodec{}, &protocol.DimensionType{}
+	r.NBT(pk.DimensionCodec)
+	r.NBT(pk.Dimension)
 	r.String(&pk.World)
 	r.Int64(&pk.HashedSeed)
 	r.Varint32(&pk.MaxPlayers)
@@ -0,0 +1,96 @@
+package packet
+
+import (
+	"github.com/justtaldevelops/expresso/expresso/protocol"
+	"sort"
+)
+
+// MultiBlockChange is sent by the server to change multiple blocks in a single chunk section client-side.
+type MultiBlockChange struct {
+	// SectionX, SectionY, SectionZ are the coordinates of the chunk section the blocks are in.
+	SectionX, SectionY, SectionZ int32
+	// TrustEdges is the inverse of the value sent with UpdateLight packets for the section.
+	TrustEdges bool
+	// Changes contains the changes of all blocks, with positions relative to the section.
+	Changes []protocol.BlockChange
+}
+
+// ID ...
+func (*MultiBlockChange) ID() int32 {
+	return 0x3F
+}
+
+// Marshal ...
+func (pk *MultiBlockChange) Marshal(w *protocol.Writer) {
+	sectionPos := int64(pk.SectionX&0x3FFFFF)<<42 | int64(pk.SectionZ&0x3FFFFF)<<20 | int64(pk.SectionY&0xFFFFF)
+	w.Int64(&sectionPos)
+	w.Bool(&pk.TrustEdges)
+
+	changesLen := int32(len(pk.Changes))
+	w.Varint32(&changesLen)
+	for _, change := range pk.Changes {
+		pos := change.Position
+		v := int64(change.State)<<12 | int64(pos.X()&15)<<8 | int64(pos.Z()&15)<<4 | int64(pos.Y()&15)
+		w.Varint64(&v)
+	}
+}
+
+// Unmarshal ...
+func (pk *MultiBlockChange) Unmarshal(r *protocol.Reader) {
+	var sectionPos int64
+	r.Int64(&sectionPos)
+	pk.SectionX, pk.SectionY, pk.SectionZ = int32(sectionPos>>42), int32(sectionPos<<44>>44), int32(sectionPos<<22>>42)
+	r.Bool(&pk.TrustEdges)
+
+	var changesLen int32
+	r.Varint32(&changesLen)
+
+	pk.Changes = make([]protocol.BlockChange, changesLen)
+	for i := int32(0); i < changesLen; i++ {
+		var v int64
+		r.Varint64(&v)
+		pk.Changes[i] = protocol.BlockChange{
+			Position: protocol.BlockPos{int32(v>>8) & 15, int32(v) & 15, int32(v>>4) & 15},
+			State:    int32(v >> 12),
+		}
+	}
+}
+
+// BlockChanges flushes the changes tracked by the column passed and returns the packets required to update
+// them client-side. Chunk sections with a single changed block result in a BlockChange packet, while all other
+// sections with changes result in a MultiBlockChange packet. The column is locked while its changes are
+// flushed.
+func BlockChanges(column *protocol.Column) []Packet {
+	column.Lock()
+	changes := column.FlushChanges()
+	column.Unlock()
+
+	chunkIndices := make([]int32, 0, len(changes))
+	for chunkIndex := range changes {
+		chunkIndices = append(chunkIndices, chunkIndex)
+	}
+	sort.Slice(chunkIndices, func(i, j int) bool { return chunkIndices[i] < chunkIndices[j] })
+
+	packets := make([]Packet, 0, len(chunkIndices))
+	for _, chunkIndex := range chunkIndices {
+		sectionChanges := changes[chunkIndex]
+		if len(sectionChanges) == 1 {
+			packets = append(packets, &BlockChange{
+				Position: column.Position.WorldPos(sectionChanges[0].Position),
+				State:    sectionChanges[0].State,
+			})
+			continue
+		}
+		// The changes flushed hold positions relative to the column, which are made relative to the section.
+		for i, change := range sectionChanges {
+			sectionChanges[i].Position = protocol.BlockPos{change.Position.X(), change.Position.Y() & 15, change.Position.Z()}
+		}
+		packets = append(packets, &MultiBlockChange{
+			SectionX: column.Position.X(),
+			SectionY: chunkIndex + column.MinY>>4,
+			SectionZ: column.Position.Z(),
+			Changes:  sectionChanges,
+		})
+	}
+	return packets
+}
@@ -0,0 +1,60 @@
+package packet
+
+import "github.com/justtaldevelops/expresso/expresso/protocol"
+
+// Respawn is sent by the server to respawn the player, or to move the player to a different world or
+// dimension. The dimension type must be present in the dimension codec sent in JoinGame.
+type Respawn struct {
+	// Dimension is the dimension type of the world the player is respawning in. If nil, the default overworld
+	// dimension type is used.
+	Dimension *protocol.DimensionType
+	// World is the name of the world the player is respawning in.
+	World string
+	// HashedSeed contains the first eight bytes of the world seed in an SHA-256 hash.
+	HashedSeed int64
+	// GameMode is the game mode of the player.
+	GameMode byte
+	// PreviousGameMode is the player's previous game mode.
+	PreviousGameMode byte
+	// Debug is true if the world is in debug mode.
+	Debug bool
+	// Flat is true if the world is flat.
+	Flat bool
+	// CopyMetadata is true if the metadata of the player, such as its potion effects, should be kept.
+	CopyMetadata bool
+}
+
+// ID ...
+func (*Respawn) ID() int32 {
+	return 0x3D
+}
+
+// Marshal ...
+func (pk *Respawn) Marshal(w *protocol.Writer) {
+	dim := pk.Dimension
+	if dim == nil {
+		dim = &defaultDimensionType
+	}
+
+	w.NBT(dim)
+	w.String(&pk.World)
+	w.Int64(&pk.HashedSeed)
+	w.Uint8(&pk.GameMode)
+	w.Uint8(&pk.PreviousGameMode)
+	w.Bool(&pk.Debug)
+	w.Bool(&pk.Flat)
+	w.Bool(&pk.CopyMetadata)
+}
+
+// Unmarshal ...
+func (pk *Respawn) Unmarshal(r *protocol.Reader) {
+	pk.Dimension = &protocol.DimensionType{}
+	r.NBT(pk.Dimension)
+	r.String(&pk.World)
+	r.Int64(&pk.HashedSeed)
+	r.Uint8(&pk.GameMode)
+	r.Uint8(&pk.PreviousGameMode)
+	r.Bool(&pk.Debug)
+	r.Bool(&pk.Flat)
+	r.Bool(&pk.CopyMetadata)
+}
@@ -0,0 +1,27 @@
+package packet
+
+import "github.com/justtaldevelops/expresso/expresso/protocol"
+
+// UnloadChunk is sent by the server to unload a chunk column client-side, usually when it is out of the
+// player's view distance.
+type UnloadChunk struct {
+	// X, Z are the coordinates of the column to unload.
+	X, Z int32
+}
+
+// ID ...
+func (*UnloadChunk) ID() int32 {
+	return 0x1D
+}
+
+// Marshal ...
+func (pk *UnloadChunk) Marshal(w *protocol.Writer) {
+	w.Int32(&pk.X)
+	w.Int32(&pk.Z)
+}
+
+// Unmarshal ...
+func (pk *UnloadChunk) Unmarshal(r *protocol.Reader) {
+	r.Int32(&pk.X)
+	r.Int32(&pk.Z)
+}
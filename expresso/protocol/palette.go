@@ -128,7 +128,7 @@ type MapPalette struct {
 	stateToID map[int32]int32
 }
 
-// NewMapPalette returns a new map palette.
+// NewMapPalette returns a new map palette. Like the list palette, air is mapped to the first ID.
 func NewMapPalette(bitsPerEntry int32) *MapPalette {
 	maxId := int32((1 << bitsPerEntry) - 1)
 
@@ -136,7 +136,7 @@ func NewMapPalette(bitsPerEntry int32) *MapPalette {
 		nextId:    1,
 		maxId:     maxId,
 		idToState: make([]int32, maxId+1),
-		stateToID: make(map[int32]int32),
+		stateToID: map[int32]int32{air: 0},
 	}
 }
 
@@ -147,6 +147,8 @@ func NewMapPaletteFromReader(bitsPerEntry int32, reader *Reader) *MapPalette {
 	var paletteLength int32
 	reader.Varint32(&paletteLength)
 
+	// The palette read does not necessarily map air to the first ID.
+	palette.stateToID = make(map[int32]int32, paletteLength)
 	for i := int32(0); i < paletteLength; i++ {
 		var state int32
 		reader.Varint32(&state)
@@ -30,3 +30,8 @@ func (p ColumnPos) X() int32 {
 func (p ColumnPos) Z() int32 {
 	return p[1]
 }
+
+// WorldPos converts a block position relative to the column to a position in the world.
+func (p ColumnPos) WorldPos(pos BlockPos) BlockPos {
+	return BlockPos{p.X()<<4 | pos.X()&15, pos.Y(), p.Z()<<4 | pos.Z()&15}
+}
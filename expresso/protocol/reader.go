@@ -160,12 +160,24 @@ func (r *Reader) UUID(x *uuid.UUID) {
 	_, _ = io.ReadFull(r, (*x)[:])
 }
 
-// Text reads Minecraft-style text from the underlying buffer.
+// BlockPos reads a block position, packed into an int64, from the underlying buffer.
+func (r *Reader) BlockPos(x *BlockPos) {
+	var v int64
+	r.Int64(&v)
+
+	*x = BlockPos{int32(v >> 38), int32(v << 52 >> 52), int32(v << 26 >> 38)}
+}
+
+// Text reads Minecraft-style text from the underlying buffer. Like other read errors, text that cannot be
+// decoded, such as text with an invalid color, causes a panic, so that callers unmarshaling packets learn of
+// the error by recovering from it.
 func (r *Reader) Text(x *text.Text) {
 	var s string
 	r.String(&s)
 
-	_ = json.Unmarshal([]byte(s), x)
+	if err := json.Unmarshal([]byte(s), x); err != nil {
+		panic(fmt.Errorf("decode text: %w", err))
+	}
 }
 
 // Chunk reads a chunk from the underlying buffer.
@@ -198,8 +210,8 @@ func (r *Reader) Chunk(x *Chunk) {
 	}
 }
 
-// NBT reads a map as a compound tag from the underlying buffer.
-func (r *Reader) NBT(x *map[string]interface{}) {
+// NBT reads a compound tag from the underlying buffer into a pointer to a map or struct.
+func (r *Reader) NBT(x interface{}) {
 	if err := nbt.NewDecoderWithEncoding(r, nbt.BigEndian).Decode(x); err != nil {
 		panic(err)
 	}
@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/justtaldevelops/expresso/expresso/nbt"
 	"github.com/justtaldevelops/expresso/expresso/text"
@@ -113,15 +114,31 @@ func (w *Writer) UUID(x *uuid.UUID) {
 	_, _ = w.Write(x[:])
 }
 
+// BlockPos writes a block position, packed into an int64, to the underlying buffer.
+func (w *Writer) BlockPos(x *BlockPos) {
+	v := int64(x.X()&0x3FFFFFF)<<38 | int64(x.Z()&0x3FFFFFF)<<12 | int64(x.Y()&0xFFF)
+	w.Int64(&v)
+}
+
 // Text writes Minecraft-style text to the underlying buffer.
 func (w *Writer) Text(x *text.Text) {
-	b, _ := json.Marshal(*x)
+	b, err := json.Marshal(*x)
+	if err != nil {
+		panic(fmt.Errorf("encode text: %w", err))
+	}
 	s := string(b)
 	w.String(&s)
 }
 
-// Chunk writes a chunk to the underlying buffer.
+// Chunk writes a chunk to the underlying buffer. If the palette of the chunk may contain unused block states,
+// the chunk is compacted before it is written.
 func (w *Writer) Chunk(x *Chunk) {
+	if x.fragmented {
+		if err := x.Compact(); err != nil {
+			panic(err)
+		}
+	}
+
 	blockCount := int16(x.blockCount)
 	bitsPerEntry := uint8(x.storage.bitsPerEntry)
 
@@ -147,9 +164,9 @@ func (w *Writer) Chunk(x *Chunk) {
 	}
 }
 
-// NBT writes a map as a compound tag to the underlying buffer.
-func (w *Writer) NBT(x *map[string]interface{}) {
-	if err := nbt.NewEncoderWithEncoding(w, nbt.BigEndian).Encode(*x); err != nil {
+// NBT writes a map or struct, or a pointer to either, as a compound tag to the underlying buffer.
+func (w *Writer) NBT(x interface{}) {
+	if err := nbt.NewEncoderWithEncoding(w, nbt.BigEndian).Encode(x); err != nil {
 		panic(err)
 	}
 }
@@ -0,0 +1,241 @@
+package expresso
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"github.com/justtaldevelops/expresso/expresso/protocol"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QueryConfig configures a QueryListener.
+type QueryConfig struct {
+	// Software is the name of the server software reported along with the plugins in full stat responses. If
+	// empty, Expresso is used.
+	Software string
+	// Plugins holds the names of the plugins reported in full stat responses.
+	Plugins []string
+	// Map is the name of the world reported. If empty, world is used.
+	Map string
+	// ChallengeExpiry is the duration after which challenge tokens handed out to clients expire. If not positive,
+	// the vanilla expiry of 30 seconds is used.
+	ChallengeExpiry time.Duration
+}
+
+// QueryListener answers queries of the GameSpy4 based Query protocol over UDP, as enabled using enable-query in
+// vanilla. The status reported is that of the StatusProvider of a Listener, so that queries report the same
+// data as the server list.
+type QueryListener struct {
+	cfg      QueryConfig
+	conn     net.PacketConn
+	listener *Listener
+	// closed is closed once the QueryListener stops listening.
+	closed chan struct{}
+
+	mu         sync.Mutex
+	challenges map[string]queryChallenge
+}
+
+// queryChallenge is a challenge token handed out to a client.
+type queryChallenge struct {
+	token   int32
+	expires time.Time
+}
+
+const (
+	// queryTypeHandshake is the type of packets requesting and holding a challenge token.
+	queryTypeHandshake = 0x09
+	// queryTypeStat is the type of packets requesting and holding a basic or full stat.
+	queryTypeStat = 0x00
+)
+
+// Listen listens for queries on the UDP address passed and answers them with the status of the Listener passed.
+// The port may be the same as that of the Listener, as the Listener uses TCP.
+func (cfg QueryConfig) Listen(address string, l *Listener) (*QueryListener, error) {
+	if cfg.Software == "" {
+		cfg.Software = "Expresso"
+	}
+	if cfg.Map == "" {
+		cfg.Map = "world"
+	}
+	if cfg.ChallengeExpiry <= 0 {
+		cfg.ChallengeExpiry = 30 * time.Second
+	}
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	q := &QueryListener{cfg: cfg, conn: conn, listener: l, closed: make(chan struct{}), challenges: make(map[string]queryChallenge)}
+	go q.startListening()
+	go q.pruneChallenges()
+	return q, nil
+}
+
+// Addr returns the address the QueryListener listens on.
+func (q *QueryListener) Addr() net.Addr {
+	return q.conn.LocalAddr()
+}
+
+// Close closes the QueryListener.
+func (q *QueryListener) Close() error {
+	return q.conn.Close()
+}
+
+// startListening reads and answers queries until the QueryListener is closed.
+func (q *QueryListener) startListening() {
+	defer close(q.closed)
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := q.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		resp, err := q.handle(buf[:n], addr)
+		if err != nil {
+			q.listener.errorLog.Printf("query from %v: %v\n", addr, err)
+			continue
+		}
+		if resp != nil {
+			_, _ = q.conn.WriteTo(resp, addr)
+		}
+	}
+}
+
+// handle handles a query sent by the address passed and returns the response to it. Like in vanilla, invalid
+// queries and queries with an invalid or expired challenge token are ignored, in which case nil is returned.
+func (q *QueryListener) handle(b []byte, addr net.Addr) ([]byte, error) {
+	if len(b) < 7 || b[0] != 0xfe || b[1] != 0xfd {
+		return nil, nil
+	}
+	packetType, session := b[2], b[3:7]
+
+	resp := &bytes.Buffer{}
+	resp.WriteByte(packetType)
+	// The session ID is echoed exactly as the client sent it, as clients match responses to it.
+	resp.Write(session)
+	switch packetType {
+	case queryTypeHandshake:
+		token, err := q.challenge(addr)
+		if err != nil {
+			return nil, err
+		}
+		resp.WriteString(strconv.Itoa(int(token)) + "\x00")
+	case queryTypeStat:
+		if len(b) < 11 || !q.validChallenge(addr, int32(binary.BigEndian.Uint32(b[7:11]))) {
+			return nil, nil
+		}
+		status := q.listener.Status(StatusRequest{RemoteAddr: addr, Query: true})
+		if len(b) >= 15 {
+			q.writeFullStat(resp, status)
+		} else {
+			q.writeBasicStat(resp, status)
+		}
+	default:
+		return nil, nil
+	}
+	return resp.Bytes(), nil
+}
+
+// challenge hands out a new challenge token to the address passed, which is valid until it expires.
+func (q *QueryListener) challenge(addr net.Addr) (int32, error) {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, fmt.Errorf("generate challenge token: %w", err)
+	}
+	// Like in vanilla, tokens are below 2^24, so that they are never negative.
+	token := int32(binary.BigEndian.Uint32(b[:]) & 0xffffff)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.challenges[addr.String()] = queryChallenge{token: token, expires: time.Now().Add(q.cfg.ChallengeExpiry)}
+	return token, nil
+}
+
+// pruneChallenges removes expired challenge tokens every time the expiry duration passes, until the
+// QueryListener is closed.
+func (q *QueryListener) pruneChallenges() {
+	t := time.NewTicker(q.cfg.ChallengeExpiry)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			q.mu.Lock()
+			now := time.Now()
+			for addr, c := range q.challenges {
+				if now.After(c.expires) {
+					delete(q.challenges, addr)
+				}
+			}
+			q.mu.Unlock()
+		case <-q.closed:
+			return
+		}
+	}
+}
+
+// validChallenge checks if the challenge token passed was handed out to the address passed and has not yet
+// expired.
+func (q *QueryListener) validChallenge(addr net.Addr, token int32) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	c, ok := q.challenges[addr.String()]
+	return ok && c.token == token && time.Now().Before(c.expires)
+}
+
+// writeBasicStat writes the payload of a basic stat response holding the status passed.
+func (q *QueryListener) writeBasicStat(buf *bytes.Buffer, status Status) {
+	host, port := q.hostPort()
+	for _, s := range []string{status.Description.Legacy(), "SMP", q.cfg.Map, strconv.Itoa(status.Players.Online), strconv.Itoa(status.Players.Max)} {
+		buf.WriteString(s + "\x00")
+	}
+	_ = binary.Write(buf, binary.LittleEndian, port)
+	buf.WriteString(host + "\x00")
+}
+
+// writeFullStat writes the payload of a full stat response holding the status passed.
+func (q *QueryListener) writeFullStat(buf *bytes.Buffer, status Status) {
+	host, port := q.hostPort()
+	plugins := q.cfg.Software
+	if len(q.cfg.Plugins) != 0 {
+		plugins += ": " + strings.Join(q.cfg.Plugins, "; ")
+	}
+	buf.WriteString("splitnum\x00\x80\x00")
+	for _, kv := range [][2]string{
+		{"hostname", status.Description.Legacy()},
+		{"gametype", "SMP"},
+		{"game_id", "MINECRAFT"},
+		{"version", protocol.CurrentMinecraftVersion},
+		{"plugins", plugins},
+		{"map", q.cfg.Map},
+		{"numplayers", strconv.Itoa(status.Players.Online)},
+		{"maxplayers", strconv.Itoa(status.Players.Max)},
+		{"hostport", strconv.Itoa(int(port))},
+		{"hostip", host},
+	} {
+		buf.WriteString(kv[0] + "\x00" + kv[1] + "\x00")
+	}
+	buf.WriteString("\x00\x01player_\x00\x00")
+	for _, p := range status.Players.Sample {
+		buf.WriteString(p.Name + "\x00")
+	}
+	buf.WriteByte(0)
+}
+
+// hostPort returns the host and port of the TCP listener reported in stat responses.
+func (q *QueryListener) hostPort() (string, uint16) {
+	addr, ok := q.listener.listener.Addr().(*net.TCPAddr)
+	if !ok {
+		return "0.0.0.0", 0
+	}
+	host := "0.0.0.0"
+	if addr.IP != nil && !addr.IP.IsUnspecified() {
+		host = addr.IP.String()
+	}
+	return host, uint16(addr.Port)
+}
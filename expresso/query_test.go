@@ -0,0 +1,130 @@
+package expresso
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/justtaldevelops/expresso/expresso/protocol"
+	"github.com/justtaldevelops/expresso/expresso/text"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// queryStatusProvider is a StatusProvider returning a fixed status.
+type queryStatusProvider struct {
+	status Status
+}
+
+// Status ...
+func (p queryStatusProvider) Status(StatusRequest) Status {
+	return p.status
+}
+
+// TestQuery tests the byte layout of the handshake, basic stat and full stat responses, and that the session
+// ID sent by the client is echoed in each of them.
+func TestQuery(t *testing.T) {
+	l, err := ListenConfig{
+		StatusProvider: queryStatusProvider{status: Status{
+			Players:     Players{Online: 2, Max: 20, Sample: []Player{{Name: "Steve"}, {Name: "Alex"}}},
+			Description: text.Text{Text: "Hi", Color: text.Gold},
+		}},
+		Query: &QueryConfig{Plugins: []string{"A", "B"}, Map: "lobby"},
+	}.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	port := l.listener.Addr().(*net.TCPAddr).Port
+
+	conn, err := net.Dial("udp", l.query.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	session := []byte{0x81, 0x02, 0x03, 0x0f}
+	query := func(b ...byte) []byte {
+		req := append([]byte{0xfe, 0xfd}, b...)
+		if _, err := conn.Write(req); err != nil {
+			t.Fatal(err)
+		}
+		resp := make([]byte, 1500)
+		n, err := conn.Read(resp)
+		if err != nil {
+			t.Fatalf("read response to %x: %v", req, err)
+		}
+		return resp[:n]
+	}
+
+	resp := query(append([]byte{queryTypeHandshake}, session...)...)
+	if !bytes.HasPrefix(resp, append([]byte{queryTypeHandshake}, session...)) || resp[len(resp)-1] != 0 {
+		t.Fatalf("invalid handshake response %q", resp)
+	}
+	token, err := strconv.Atoi(string(resp[5 : len(resp)-1]))
+	if err != nil || token < 0 || token >= 1<<24 {
+		t.Fatalf("invalid challenge token %q", resp[5:len(resp)-1])
+	}
+	var tokenBytes [4]byte
+	binary.BigEndian.PutUint32(tokenBytes[:], uint32(token))
+
+	expected := &bytes.Buffer{}
+	expected.WriteByte(queryTypeStat)
+	expected.Write(session)
+	expected.WriteString("§6Hi\x00SMP\x00lobby\x002\x0020\x00")
+	_ = binary.Write(expected, binary.LittleEndian, uint16(port))
+	expected.WriteString("127.0.0.1\x00")
+	if resp := query(append(append([]byte{queryTypeStat}, session...), tokenBytes[:]...)...); !bytes.Equal(resp, expected.Bytes()) {
+		t.Errorf("basic stat:\nexpected %q\ngot      %q", expected.Bytes(), resp)
+	}
+
+	expected = &bytes.Buffer{}
+	expected.WriteByte(queryTypeStat)
+	expected.Write(session)
+	expected.WriteString("splitnum\x00\x80\x00" +
+		"hostname\x00§6Hi\x00gametype\x00SMP\x00game_id\x00MINECRAFT\x00version\x00" + protocol.CurrentMinecraftVersion + "\x00" +
+		"plugins\x00Expresso: A; B\x00map\x00lobby\x00numplayers\x002\x00maxplayers\x0020\x00" +
+		"hostport\x00" + strconv.Itoa(port) + "\x00hostip\x00127.0.0.1\x00\x00" +
+		"\x01player_\x00\x00Steve\x00Alex\x00\x00")
+	if resp := query(append(append(append([]byte{queryTypeStat}, session...), tokenBytes[:]...), 0, 0, 0, 0)...); !bytes.Equal(resp, expected.Bytes()) {
+		t.Errorf("full stat:\nexpected %q\ngot      %q", expected.Bytes(), resp)
+	}
+}
+
+// TestQueryIgnored tests that invalid queries and stat requests without a valid challenge token are not
+// answered.
+func TestQueryIgnored(t *testing.T) {
+	q := &QueryListener{cfg: QueryConfig{ChallengeExpiry: time.Minute}, challenges: make(map[string]queryChallenge)}
+	addr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234}
+	other := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1235}
+	token, err := q.challenge(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tokenBytes [4]byte
+	binary.BigEndian.PutUint32(tokenBytes[:], uint32(token))
+	var wrongToken [4]byte
+	binary.BigEndian.PutUint32(wrongToken[:], uint32(token)+1)
+
+	for _, test := range []struct {
+		b    []byte
+		addr net.Addr
+	}{
+		{[]byte{0xfe, 0xfd, queryTypeHandshake, 1, 2, 3}, addr},
+		{[]byte{0xfe, 0xfc, queryTypeHandshake, 1, 2, 3, 4}, addr},
+		{[]byte{0xfe, 0xfd, 0x05, 1, 2, 3, 4}, addr},
+		{[]byte{0xfe, 0xfd, queryTypeStat, 1, 2, 3, 4, tokenBytes[0], tokenBytes[1]}, addr},
+		{append([]byte{0xfe, 0xfd, queryTypeStat, 1, 2, 3, 4}, wrongToken[:]...), addr},
+		{append([]byte{0xfe, 0xfd, queryTypeStat, 1, 2, 3, 4}, tokenBytes[:]...), other},
+	} {
+		resp, err := q.handle(test.b, test.addr)
+		if err != nil || resp != nil {
+			t.Errorf("%x from %v: expected query to be ignored, got %q, %v", test.b, test.addr, resp, err)
+		}
+	}
+
+	q.challenges[addr.String()] = queryChallenge{token: token, expires: time.Now().Add(-time.Second)}
+	if resp, _ := q.handle(append([]byte{0xfe, 0xfd, queryTypeStat, 1, 2, 3, 4}, tokenBytes[:]...), addr); resp != nil {
+		t.Errorf("expected query with expired token to be ignored, got %q", resp)
+	}
+}
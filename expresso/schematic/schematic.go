@@ -0,0 +1,298 @@
+package schematic
+
+import (
+	"fmt"
+	"github.com/justtaldevelops/expresso/expresso/nbt"
+	"github.com/justtaldevelops/expresso/expresso/protocol"
+	"github.com/justtaldevelops/expresso/expresso/world"
+	"io"
+	"reflect"
+)
+
+// currentDataVersion is the data version of the current version of Minecraft, written to saved files.
+const currentDataVersion = 2730
+
+// Schematic is a cuboid of blocks and block entities that may be loaded from and saved to schematic files,
+// and pasted into columns.
+type Schematic struct {
+	// Width, Height and Length are the size of the schematic on the X, Y and Z axis respectively.
+	Width, Height, Length int32
+	// Offset is the position of the schematic relative to the position it is pasted at.
+	Offset protocol.BlockPos
+	// DataVersion is the data version of Minecraft the schematic was saved in.
+	DataVersion int32
+	// Palette contains the block state names used in the schematic, such as minecraft:oak_log[axis=y].
+	Palette []string
+	// Blocks contains an index into the palette for every block in the schematic, ordered by Y, then Z, then X.
+	Blocks []int32
+	// BlockEntities contains all block entities in the schematic.
+	BlockEntities []BlockEntity
+	// Metadata holds any additional data of the schematic, such as its name and author. It is only kept for
+	// Sponge schematics.
+	Metadata map[string]interface{}
+}
+
+// BlockEntity is a block entity in a schematic.
+type BlockEntity struct {
+	// Position is the position of the block entity relative to the schematic.
+	Position protocol.BlockPos
+	// ID is the namespaced identifier of the block entity, such as minecraft:chest.
+	ID string
+	// Data is the NBT data of the block entity, excluding its identifier and position.
+	Data map[string]interface{}
+}
+
+// New returns a new schematic of the size passed, filled with air.
+func New(width, height, length int32) *Schematic {
+	return &Schematic{
+		Width:       width,
+		Height:      height,
+		Length:      length,
+		DataVersion: currentDataVersion,
+		Palette:     []string{"minecraft:air"},
+		Blocks:      make([]int32, width*height*length),
+	}
+}
+
+// Read reads a schematic from the reader passed, which may be a Sponge schematic of version two or three, or a
+// vanilla structure file. The data may be gzip or zlib compressed, or uncompressed.
+func Read(r io.Reader) (*Schematic, error) {
+	m, err := readNBT(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := m["palette"]; ok {
+		return structureFromNBT(m)
+	}
+	return spongeFromNBT(m)
+}
+
+// Index returns the index of the block at the position passed, relative to the schematic, in Blocks.
+func (s *Schematic) Index(x, y, z int32) int32 {
+	return (y*s.Length+z)*s.Width + x
+}
+
+// Block returns the block state name of the block at the position passed, relative to the schematic.
+func (s *Schematic) Block(x, y, z int32) string {
+	return s.Palette[s.Blocks[s.Index(x, y, z)]]
+}
+
+// SetBlock sets the block at the position passed, relative to the schematic, to the block state name passed.
+func (s *Schematic) SetBlock(x, y, z int32, name string) {
+	id := -1
+	for i, n := range s.Palette {
+		if n == name {
+			id = i
+			break
+		}
+	}
+	if id == -1 {
+		id = len(s.Palette)
+		s.Palette = append(s.Palette, name)
+	}
+	s.Blocks[s.Index(x, y, z)] = int32(id)
+}
+
+// Copy copies the blocks and block entities between the world positions min and max passed, inclusive, out of
+// the columns passed into a new schematic. Block state IDs are mapped to names using the registry passed.
+// Missing columns are treated as air.
+func Copy(columns map[protocol.ColumnPos]*protocol.Column, min, max protocol.BlockPos, blocks world.BlockRegistry) (*Schematic, error) {
+	for i := range min {
+		if min[i] > max[i] {
+			min[i], max[i] = max[i], min[i]
+		}
+	}
+	s := New(max.X()-min.X()+1, max.Y()-min.Y()+1, max.Z()-min.Z()+1)
+
+	palette := map[int32]int32{}
+	s.Palette = s.Palette[:0]
+	for y := int32(0); y < s.Height; y++ {
+		for z := int32(0); z < s.Length; z++ {
+			for x := int32(0); x < s.Width; x++ {
+				pos := protocol.BlockPos{min.X() + x, min.Y() + y, min.Z() + z}
+				column, local := columnAt(columns, pos)
+
+				state := int32(0)
+				if column != nil && pos.Y() >= column.MinY && pos.Y() <= column.MaxY() {
+					var err error
+					if state, err = column.GetBlockState(local); err != nil {
+						return nil, err
+					}
+				}
+				id, ok := palette[state]
+				if !ok {
+					name, ok := blocks.StateName(state)
+					if !ok {
+						return nil, fmt.Errorf("unknown block state %v at %v", state, pos)
+					}
+					id = int32(len(s.Palette))
+					palette[state] = id
+					s.Palette = append(s.Palette, name)
+				}
+				s.Blocks[s.Index(x, y, z)] = id
+			}
+		}
+	}
+
+	for pos, column := range columns {
+		for local, be := range column.BlockEntities {
+			worldPos := pos.WorldPos(local)
+			if worldPos.X() < min.X() || worldPos.Y() < min.Y() || worldPos.Z() < min.Z() ||
+				worldPos.X() > max.X() || worldPos.Y() > max.Y() || worldPos.Z() > max.Z() {
+				continue
+			}
+			s.BlockEntities = append(s.BlockEntities, BlockEntity{
+				Position: protocol.BlockPos{worldPos.X() - min.X(), worldPos.Y() - min.Y(), worldPos.Z() - min.Z()},
+				ID:       be.ID(),
+				Data:     be.EncodeNBT(),
+			})
+		}
+	}
+	return s, nil
+}
+
+// Paste pastes the blocks and block entities of the schematic into the columns passed, with the schematic
+// placed at the world position passed plus its offset. Block state names are mapped to IDs using the registry
+// passed. Columns that are missing from the map are created using protocol.NewColumn and added to it.
+func (s *Schematic) Paste(columns map[protocol.ColumnPos]*protocol.Column, pos protocol.BlockPos, blocks world.BlockRegistry) error {
+	if int32(len(s.Blocks)) != s.Width*s.Height*s.Length {
+		return fmt.Errorf("schematic has %v blocks, expected %v", len(s.Blocks), s.Width*s.Height*s.Length)
+	}
+	origin := protocol.BlockPos{pos.X() + s.Offset.X(), pos.Y() + s.Offset.Y(), pos.Z() + s.Offset.Z()}
+
+	// Resolve all states up front, so that we do not need to look up the same name for every block.
+	states := make([]int32, len(s.Palette))
+	for i, name := range s.Palette {
+		if name == "minecraft:structure_void" {
+			states[i] = -1
+			continue
+		}
+		state, ok := blocks.StateID(name)
+		if !ok {
+			return fmt.Errorf("unknown block state %v in palette", name)
+		}
+		states[i] = state
+	}
+
+	for y := int32(0); y < s.Height; y++ {
+		for z := int32(0); z < s.Length; z++ {
+			for x := int32(0); x < s.Width; x++ {
+				id := s.Blocks[s.Index(x, y, z)]
+				if id < 0 || int(id) >= len(states) {
+					return fmt.Errorf("block at %v %v %v has invalid palette index %v", x, y, z, id)
+				}
+				if states[id] == -1 {
+					continue
+				}
+				worldPos := protocol.BlockPos{origin.X() + x, origin.Y() + y, origin.Z() + z}
+				column, local := columnAt(columns, worldPos)
+				if column == nil {
+					column = protocol.NewColumn(protocol.ColumnPos{worldPos.X() >> 4, worldPos.Z() >> 4})
+					columns[column.Position] = column
+				}
+				if err := column.SetBlockState(local, states[id]); err != nil {
+					return fmt.Errorf("paste block at %v: %w", worldPos, err)
+				}
+			}
+		}
+	}
+
+	for _, be := range s.BlockEntities {
+		worldPos := protocol.BlockPos{origin.X() + be.Position.X(), origin.Y() + be.Position.Y(), origin.Z() + be.Position.Z()}
+		m := make(map[string]interface{}, len(be.Data)+4)
+		for k, v := range be.Data {
+			m[k] = v
+		}
+		m["id"], m["x"], m["y"], m["z"] = be.ID, worldPos.X(), worldPos.Y(), worldPos.Z()
+
+		_, blockEntity, err := protocol.DecodeBlockEntity(m)
+		if err != nil {
+			return err
+		}
+		column, local := columnAt(columns, worldPos)
+		if column == nil {
+			continue
+		}
+		if err := column.SetBlockEntity(local, blockEntity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnAt returns the column that the world position passed is in, and the position relative to that column.
+// If the column is not in the map passed, nil is returned.
+func columnAt(columns map[protocol.ColumnPos]*protocol.Column, pos protocol.BlockPos) (*protocol.Column, protocol.BlockPos) {
+	return columns[protocol.ColumnPos{pos.X() >> 4, pos.Z() >> 4}], protocol.BlockPos{pos.X() & 15, pos.Y(), pos.Z() & 15}
+}
+
+// readNBT reads a compound tag from the reader passed, which may be compressed.
+func readNBT(r io.Reader) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	if _, err := nbt.Read(r, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// writeNBT writes the value passed as a gzip compressed compound tag with the root name passed.
+func writeNBT(w io.Writer, v interface{}, name string) error {
+	return nbt.Write(w, v, nbt.FileInfo{Name: name, Compression: nbt.CompressionGzip})
+}
+
+// toInt32 converts any integer tag value passed to an int32. False is returned if the value is not an integer.
+func toInt32(v interface{}) (int32, bool) {
+	switch v := v.(type) {
+	case byte:
+		return int32(v), true
+	case int16:
+		return int32(v), true
+	case int32:
+		return v, true
+	case int64:
+		return int32(v), true
+	}
+	return 0, false
+}
+
+// toBlockPos converts an int array or a list of ints to a block position. False is returned if the value is
+// neither or does not have three elements.
+func toBlockPos(v interface{}) (protocol.BlockPos, bool) {
+	switch v := v.(type) {
+	case [3]int32:
+		return v, true
+	case []interface{}:
+		if len(v) != 3 {
+			return protocol.BlockPos{}, false
+		}
+		var pos protocol.BlockPos
+		for i, c := range v {
+			n, ok := toInt32(c)
+			if !ok {
+				return protocol.BlockPos{}, false
+			}
+			pos[i] = n
+		}
+		return pos, true
+	}
+	return protocol.BlockPos{}, false
+}
+
+// toBytes converts a TAG_ByteArray value, which is decoded as a byte array of any length, to a byte slice.
+// False is returned if the value is not a byte array.
+func toBytes(v interface{}) ([]byte, bool) {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Array || val.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, false
+	}
+	b := make([]byte, val.Len())
+	reflect.Copy(reflect.ValueOf(b), val)
+	return b, true
+}
+
+// fromBytes converts a byte slice to a byte array of the same length, which is encoded as a TAG_ByteArray.
+func fromBytes(b []byte) interface{} {
+	val := reflect.New(reflect.ArrayOf(len(b), reflect.TypeOf(byte(0)))).Elem()
+	reflect.Copy(val, reflect.ValueOf(b))
+	return val.Interface()
+}
@@ -0,0 +1,192 @@
+package schematic
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// WriteSponge writes the schematic to the writer passed as a gzip compressed Sponge schematic. The version
+// passed must be either two or three.
+func WriteSponge(w io.Writer, s *Schematic, version int32) error {
+	palette := make(map[string]interface{}, len(s.Palette))
+	for i, name := range s.Palette {
+		palette[name] = int32(i)
+	}
+	data := &bytes.Buffer{}
+	for _, id := range s.Blocks {
+		writeVarint(data, uint32(id))
+	}
+
+	blockEntities := make([]interface{}, 0, len(s.BlockEntities))
+	for _, be := range s.BlockEntities {
+		m := map[string]interface{}{
+			"Id":  be.ID,
+			"Pos": [3]int32(be.Position),
+		}
+		if version == 2 {
+			for k, v := range be.Data {
+				m[k] = v
+			}
+		} else {
+			m["Data"] = nonNilMap(be.Data)
+		}
+		blockEntities = append(blockEntities, m)
+	}
+
+	m := map[string]interface{}{
+		"Version":     version,
+		"DataVersion": s.DataVersion,
+		"Width":       int16(s.Width),
+		"Height":      int16(s.Height),
+		"Length":      int16(s.Length),
+		"Offset":      [3]int32(s.Offset),
+	}
+	if len(s.Metadata) > 0 {
+		m["Metadata"] = s.Metadata
+	}
+
+	switch version {
+	case 2:
+		m["PaletteMax"] = int32(len(s.Palette))
+		m["Palette"] = palette
+		m["BlockData"] = fromBytes(data.Bytes())
+		m["BlockEntities"] = blockEntities
+		return writeNBT(w, m, "Schematic")
+	case 3:
+		m["Blocks"] = map[string]interface{}{
+			"Palette":       palette,
+			"Data":          fromBytes(data.Bytes()),
+			"BlockEntities": blockEntities,
+		}
+		return writeNBT(w, map[string]interface{}{"Schematic": m}, "")
+	}
+	return fmt.Errorf("unsupported sponge schematic version %v", version)
+}
+
+// spongeFromNBT decodes a Sponge schematic of version two or three from its NBT representation.
+func spongeFromNBT(m map[string]interface{}) (*Schematic, error) {
+	if nested, ok := m["Schematic"].(map[string]interface{}); ok {
+		// Version three nests all data in a compound named Schematic.
+		m = nested
+	}
+	version, _ := toInt32(m["Version"])
+
+	var (
+		palette       map[string]interface{}
+		data          []byte
+		blockEntities []interface{}
+	)
+	switch version {
+	case 1, 2:
+		palette, _ = m["Palette"].(map[string]interface{})
+		data, _ = toBytes(m["BlockData"])
+		blockEntities, _ = m["BlockEntities"].([]interface{})
+		if blockEntities == nil {
+			// Version one used the name TileEntities instead.
+			blockEntities, _ = m["TileEntities"].([]interface{})
+		}
+	case 3:
+		blocks, _ := m["Blocks"].(map[string]interface{})
+		palette, _ = blocks["Palette"].(map[string]interface{})
+		data, _ = toBytes(blocks["Data"])
+		blockEntities, _ = blocks["BlockEntities"].([]interface{})
+	default:
+		return nil, fmt.Errorf("unsupported sponge schematic version %v", version)
+	}
+
+	width, _ := toInt32(m["Width"])
+	height, _ := toInt32(m["Height"])
+	length, _ := toInt32(m["Length"])
+	s := New(uint16Size(width), uint16Size(height), uint16Size(length))
+	s.DataVersion, _ = toInt32(m["DataVersion"])
+	s.Metadata, _ = m["Metadata"].(map[string]interface{})
+	if offset, ok := toBlockPos(m["Offset"]); ok {
+		s.Offset = offset
+	}
+
+	s.Palette = make([]string, len(palette))
+	for name, v := range palette {
+		id, ok := toInt32(v)
+		if !ok || id < 0 || int(id) >= len(palette) {
+			return nil, fmt.Errorf("invalid palette index %v for %v", v, name)
+		}
+		s.Palette[id] = name
+	}
+
+	r := bytes.NewReader(data)
+	for i := range s.Blocks {
+		id, err := readVarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("read block data: %w", err)
+		}
+		if int(id) >= len(s.Palette) {
+			return nil, fmt.Errorf("block %v has invalid palette index %v", i, id)
+		}
+		s.Blocks[i] = int32(id)
+	}
+
+	for _, v := range blockEntities {
+		be, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pos, ok := toBlockPos(be["Pos"])
+		if !ok {
+			return nil, fmt.Errorf("block entity has invalid position")
+		}
+		id, _ := be["Id"].(string)
+
+		beData, ok := be["Data"].(map[string]interface{})
+		if !ok {
+			// Before version three, block entity data was stored next to its position and identifier.
+			beData = make(map[string]interface{}, len(be))
+			for k, v := range be {
+				if k != "Id" && k != "Pos" {
+					beData[k] = v
+				}
+			}
+		}
+		s.BlockEntities = append(s.BlockEntities, BlockEntity{Position: pos, ID: id, Data: beData})
+	}
+	return s, nil
+}
+
+// uint16Size converts a size stored as a TAG_Short to an int32. Sponge schematics store sizes as unsigned
+// shorts, so negative sizes are wrapped around.
+func uint16Size(v int32) int32 {
+	return int32(uint16(v))
+}
+
+// nonNilMap returns the map passed, or an empty map if it is nil.
+func nonNilMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+// writeVarint writes an unsigned variable int to the buffer passed.
+func writeVarint(buf *bytes.Buffer, v uint32) {
+	for v >= 0x80 {
+		buf.WriteByte(byte(v) | 0x80)
+		v >>= 7
+	}
+	buf.WriteByte(byte(v))
+}
+
+// readVarint reads an unsigned variable int from the reader passed.
+func readVarint(r io.ByteReader) (uint32, error) {
+	var v uint32
+	for i := uint(0); i < 35; i += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		v |= uint32(b&0x7f) << i
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("varint is too big")
+}
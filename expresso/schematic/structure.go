@@ -0,0 +1,146 @@
+package schematic
+
+import (
+	"fmt"
+	"github.com/justtaldevelops/expresso/expresso/protocol"
+	"github.com/justtaldevelops/expresso/expresso/world"
+	"io"
+)
+
+// WriteStructure writes the schematic to the writer passed as a gzip compressed vanilla structure file, as
+// saved by structure blocks. Structure voids are left out of the structure, and the offset of the schematic is
+// not saved.
+func WriteStructure(w io.Writer, s *Schematic) error {
+	// Structure voids are not written to the palette, so the palette indices of other blocks shift.
+	palette := make([]interface{}, 0, len(s.Palette))
+	states := make([]int32, len(s.Palette))
+	for i, name := range s.Palette {
+		if name == "minecraft:structure_void" {
+			states[i] = -1
+			continue
+		}
+		states[i] = int32(len(palette))
+		blockName, properties, err := world.ParseStateName(name)
+		if err != nil {
+			return err
+		}
+		entry := map[string]interface{}{"Name": blockName}
+		if len(properties) > 0 {
+			props := make(map[string]interface{}, len(properties))
+			for k, v := range properties {
+				props[k] = v
+			}
+			entry["Properties"] = props
+		}
+		palette = append(palette, entry)
+	}
+
+	blockEntities := make(map[protocol.BlockPos]BlockEntity, len(s.BlockEntities))
+	for _, be := range s.BlockEntities {
+		blockEntities[be.Position] = be
+	}
+
+	blocks := make([]interface{}, 0)
+	for y := int32(0); y < s.Height; y++ {
+		for z := int32(0); z < s.Length; z++ {
+			for x := int32(0); x < s.Width; x++ {
+				state := states[s.Blocks[s.Index(x, y, z)]]
+				if state == -1 {
+					continue
+				}
+				pos := protocol.BlockPos{x, y, z}
+				block := map[string]interface{}{
+					"state": state,
+					"pos":   []int32{x, y, z},
+				}
+				if be, ok := blockEntities[pos]; ok {
+					data := make(map[string]interface{}, len(be.Data)+1)
+					for k, v := range be.Data {
+						data[k] = v
+					}
+					data["id"] = be.ID
+					block["nbt"] = data
+				}
+				blocks = append(blocks, block)
+			}
+		}
+	}
+
+	return writeNBT(w, map[string]interface{}{
+		"DataVersion": s.DataVersion,
+		"size":        []int32{s.Width, s.Height, s.Length},
+		"palette":     palette,
+		"blocks":      blocks,
+		"entities":    []interface{}{},
+	}, "")
+}
+
+// maxStructureVolume is the maximum number of blocks in a structure that is read. It is far larger than the
+// structures saved by structure blocks, which are at most 48 blocks in every direction, but keeps malformed
+// files from allocating without bound.
+const maxStructureVolume = 1 << 24
+
+// structureFromNBT decodes a vanilla structure from its NBT representation.
+func structureFromNBT(m map[string]interface{}) (*Schematic, error) {
+	size, ok := toBlockPos(m["size"])
+	if !ok || size.X() <= 0 || size.Y() <= 0 || size.Z() <= 0 || int64(size.X())*int64(size.Y())*int64(size.Z()) > maxStructureVolume {
+		return nil, fmt.Errorf("structure has invalid size %v", m["size"])
+	}
+	s := New(size.X(), size.Y(), size.Z())
+	s.DataVersion, _ = toInt32(m["DataVersion"])
+
+	palette, _ := m["palette"].([]interface{})
+	if palette == nil {
+		// Structures with multiple palettes, such as shipwrecks, store them in a list named palettes. We
+		// always use the first one.
+		if palettes, ok := m["palettes"].([]interface{}); ok && len(palettes) > 0 {
+			palette, _ = palettes[0].([]interface{})
+		}
+	}
+
+	// Blocks that are not in the structure are structure voids, which are left alone when pasting.
+	s.Palette = []string{"minecraft:structure_void"}
+	for _, v := range palette {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("structure has invalid palette entry")
+		}
+		name, _ := entry["Name"].(string)
+		properties := map[string]string{}
+		if props, ok := entry["Properties"].(map[string]interface{}); ok {
+			for k, v := range props {
+				properties[k], _ = v.(string)
+			}
+		}
+		s.Palette = append(s.Palette, world.EncodeStateName(name, properties))
+	}
+
+	blocks, _ := m["blocks"].([]interface{})
+	for _, v := range blocks {
+		block, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("structure has invalid block")
+		}
+		state, ok := toInt32(block["state"])
+		if !ok || state < 0 || int(state) >= len(s.Palette)-1 {
+			return nil, fmt.Errorf("structure block has invalid state %v", block["state"])
+		}
+		pos, ok := toBlockPos(block["pos"])
+		if !ok || pos.X() < 0 || pos.Y() < 0 || pos.Z() < 0 || pos.X() >= s.Width || pos.Y() >= s.Height || pos.Z() >= s.Length {
+			return nil, fmt.Errorf("structure block has invalid position %v", block["pos"])
+		}
+		s.Blocks[s.Index(pos.X(), pos.Y(), pos.Z())] = state + 1
+
+		if data, ok := block["nbt"].(map[string]interface{}); ok {
+			id, _ := data["id"].(string)
+			beData := make(map[string]interface{}, len(data))
+			for k, v := range data {
+				if k != "id" {
+					beData[k] = v
+				}
+			}
+			s.BlockEntities = append(s.BlockEntities, BlockEntity{Position: pos, ID: id, Data: beData})
+		}
+	}
+	return s, nil
+}
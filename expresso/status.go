@@ -5,6 +5,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/justtaldevelops/expresso/expresso/protocol"
 	"github.com/justtaldevelops/expresso/expresso/text"
+	"math/rand"
+	"net"
 )
 
 // Players represents the part of the listener that holds a sample of players.
@@ -26,29 +28,87 @@ type Player struct {
 	ID   uuid.UUID `json:"id"`
 }
 
+// StatusRequest holds information about a client requesting the status of the listener, so that the status
+// may be different for every client.
+type StatusRequest struct {
+	// Listener is the listener of which the status is requested.
+	Listener *Listener
+	// RemoteAddr is the address of the client.
+	RemoteAddr net.Addr
+	// Protocol is the protocol version of the client, which may be used to respond differently to clients with
+	// a version the listener does not support.
+	Protocol int32
+	// Address and Port are the address and port the client used to connect to the listener, which may be used
+	// to respond differently depending on the virtual host used.
+	Address string
+	Port    uint16
+	// Query is true if the status is requested using the Query protocol. The sample of players is then shown
+	// as the full list of players, so it should hold all players.
+	Query bool
+}
+
 // StatusProvider provides the status of the listener when requested.
 type StatusProvider interface {
-	// Status returns the status of the listener.
-	Status() Status
+	// Status returns the status of the listener for the request passed.
+	Status(req StatusRequest) Status
 }
 
-// DefaultStatusProvider is the default status of the listener.
+// DefaultStatusProvider is the default status of the listener. It reports the players connected to the
+// listener using a TrackingStatusProvider.
 type DefaultStatusProvider struct{}
 
 // Status returns the status of the listener.
-func (d *DefaultStatusProvider) Status() Status {
+func (d *DefaultStatusProvider) Status(req StatusRequest) Status {
+	p := TrackingStatusProvider{
+		Description: text.New("An Expresso Listener").Color(text.Gold).Bold(true).Italic(true).Build(),
+		MaxPlayers:  10,
+	}
+	return p.Status(req)
+}
+
+// TrackingStatusProvider is a StatusProvider that reports the number of players connected to the listener and
+// a random sample of their names. All players are reported to queries of the Query protocol.
+type TrackingStatusProvider struct {
+	// Description is the message of the day shown in the server list.
+	Description text.Text
+	// Favicon is the icon of the server. It may be created using LoadFavicon, FaviconFromPNG or
+	// FaviconFromImage.
+	Favicon string
+	// MaxPlayers is the maximum number of players reported.
+	MaxPlayers int
+	// SampleSize is the maximum number of players in the sample of players, which is shown when hovering over
+	// the player count. If zero, the vanilla sample size of 12 is used.
+	SampleSize int
+}
+
+// Status returns the status of the listener with the players connected to it.
+func (p *TrackingStatusProvider) Status(req StatusRequest) Status {
+	var players []Player
+	if req.Listener != nil {
+		players = req.Listener.Players()
+	}
+	online, size := len(players), p.SampleSize
+	if size == 0 {
+		size = 12
+	}
+	rand.Shuffle(len(players), func(i, j int) {
+		players[i], players[j] = players[j], players[i]
+	})
+	if len(players) > size && !req.Query {
+		players = players[:size]
+	}
 	return Status{
 		Version: Version{
 			Name:     protocol.CurrentVersion,
 			Protocol: protocol.CurrentProtocol,
 		},
-		Players: Players{Online: 9, Max: 10, Sample: []Player{}},
-		Description: text.Text{
-			Text:   "An Expresso Listener",
-			Color:  "gold",
-			Bold:   true,
-			Italic: true,
+		Players: Players{
+			Online: online,
+			Max:    p.MaxPlayers,
+			Sample: append([]Player{}, players...),
 		},
+		Description: p.Description,
+		Favicon:     p.Favicon,
 	}
 }
 
@@ -57,7 +117,9 @@ type Status struct {
 	Version     Version   `json:"version"`
 	Players     Players   `json:"players"`
 	Description text.Text `json:"description"`
-	Favicon     string    `json:"favicon,omitempty"`
+	// Favicon is the icon of the server, which is a PNG image encoded as data URL. It may be created using
+	// LoadFavicon, FaviconFromPNG or FaviconFromImage.
+	Favicon string `json:"favicon,omitempty"`
 }
 
 // String returns the status as a string.
@@ -0,0 +1,154 @@
+package text
+
+import (
+	"encoding/json"
+)
+
+// Builder is used to build text components in a fluent way, such as:
+//
+//	text.New("Click").Color(text.Gold).OnClick(text.RunCommand("/spawn")).Build()
+//
+// Every method of a Builder returns the same Builder, so that calls may be chained.
+type Builder struct {
+	t Text
+}
+
+// New returns a Builder for a component with the literal text passed.
+func New(s string) *Builder {
+	return &Builder{t: Text{Text: s}}
+}
+
+// NewTranslatable returns a Builder for a component that displays the translation of the key passed, with the
+// arguments passed substituted in it.
+func NewTranslatable(key string, args ...Text) *Builder {
+	return &Builder{t: Text{Translate: key, With: encodeArgs(args)}}
+}
+
+// NewKeybind returns a Builder for a component that displays the key bound to the keybind passed, such as
+// key.jump.
+func NewKeybind(keybind string) *Builder {
+	return &Builder{t: Text{Keybind: keybind}}
+}
+
+// NewScore returns a Builder for a component that displays the score of the entity with the name passed in
+// the objective passed.
+func NewScore(name, objective string) *Builder {
+	return &Builder{t: Text{Score: &Score{Name: name, Objective: objective}}}
+}
+
+// NewSelector returns a Builder for a component that displays the names of the entities matched by the
+// selector passed.
+func NewSelector(selector string) *Builder {
+	return &Builder{t: Text{Selector: selector}}
+}
+
+// NewBlockNBT returns a Builder for a component that displays the NBT value at the path passed of the block
+// entity at the coordinates passed, such as ~ ~-1 ~.
+func NewBlockNBT(path, block string) *Builder {
+	return &Builder{t: Text{NBT: path, Block: block}}
+}
+
+// NewEntityNBT returns a Builder for a component that displays the NBT values at the path passed of the
+// entities matched by the selector passed.
+func NewEntityNBT(path, selector string) *Builder {
+	return &Builder{t: Text{NBT: path, Entity: selector}}
+}
+
+// NewStorageNBT returns a Builder for a component that displays the NBT value at the path passed of the
+// command storage with the identifier passed.
+func NewStorageNBT(path, storage string) *Builder {
+	return &Builder{t: Text{NBT: path, Storage: storage}}
+}
+
+// Color sets the color of the component. It is either one of the named colors, such as Gold, or a hex color
+// returned by Hex.
+func (b *Builder) Color(color string) *Builder {
+	b.t.Color = color
+	return b
+}
+
+// Font sets the font of the component, such as minecraft:uniform.
+func (b *Builder) Font(font string) *Builder {
+	b.t.Font = font
+	return b
+}
+
+// Bold sets whether the component is bold.
+func (b *Builder) Bold(bold bool) *Builder {
+	b.t.SetDecoration(DecorationBold, bold)
+	return b
+}
+
+// Italic sets whether the component is italic.
+func (b *Builder) Italic(italic bool) *Builder {
+	b.t.SetDecoration(DecorationItalic, italic)
+	return b
+}
+
+// Underlined sets whether the component is underlined.
+func (b *Builder) Underlined(underlined bool) *Builder {
+	b.t.SetDecoration(DecorationUnderlined, underlined)
+	return b
+}
+
+// Strikethrough sets whether the component is struck through.
+func (b *Builder) Strikethrough(strikethrough bool) *Builder {
+	b.t.SetDecoration(DecorationStrikethrough, strikethrough)
+	return b
+}
+
+// Obfuscated sets whether the component is obfuscated.
+func (b *Builder) Obfuscated(obfuscated bool) *Builder {
+	b.t.SetDecoration(DecorationObfuscated, obfuscated)
+	return b
+}
+
+// Insertion sets the text inserted into the chat box when the component is shift-clicked.
+func (b *Builder) Insertion(insertion string) *Builder {
+	b.t.Insertion = insertion
+	return b
+}
+
+// Separator sets the separator used between the values of a selector or NBT component.
+func (b *Builder) Separator(separator Text) *Builder {
+	b.t.Separator = &separator
+	return b
+}
+
+// Interpret sets whether the value of an NBT component is interpreted as a text component.
+func (b *Builder) Interpret(interpret bool) *Builder {
+	b.t.Interpret = interpret
+	return b
+}
+
+// OnClick sets the event that occurs when the component is clicked, such as one returned by RunCommand.
+func (b *Builder) OnClick(event *ClickEvent) *Builder {
+	b.t.ClickEvent = event
+	return b
+}
+
+// OnHover sets the event that occurs when the component is hovered over, such as one returned by ShowText.
+func (b *Builder) OnHover(event *HoverEvent) *Builder {
+	b.t.HoverEvent = event
+	return b
+}
+
+// Append appends the components passed to the component. They inherit its style.
+func (b *Builder) Append(extra ...Text) *Builder {
+	b.t.Extra = append(b.t.Extra, extra...)
+	return b
+}
+
+// AppendText appends a component with the literal text passed to the component.
+func (b *Builder) AppendText(s string) *Builder {
+	return b.Append(Text{Text: s})
+}
+
+// Build returns the component built.
+func (b *Builder) Build() Text {
+	// Copy the slices so that further calls to the Builder do not change the component returned.
+	t := b.t
+	t.Extra = append([]Text(nil), b.t.Extra...)
+	t.With = append([]json.RawMessage(nil), b.t.With...)
+	return t
+}
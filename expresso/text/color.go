@@ -0,0 +1,63 @@
+package text
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// The named colors that may be used as the color of a text component.
+const (
+	Black       = "black"
+	DarkBlue    = "dark_blue"
+	DarkGreen   = "dark_green"
+	DarkAqua    = "dark_aqua"
+	DarkRed     = "dark_red"
+	DarkPurple  = "dark_purple"
+	Gold        = "gold"
+	Gray        = "gray"
+	DarkGray    = "dark_gray"
+	Blue        = "blue"
+	Green       = "green"
+	Aqua        = "aqua"
+	Red         = "red"
+	LightPurple = "light_purple"
+	Yellow      = "yellow"
+	White       = "white"
+	// Reset resets the color to the default color of the client.
+	Reset = "reset"
+)
+
+// Hex returns a hex color of the form #rrggbb from the red, green and blue components passed.
+func Hex(r, g, b uint8) string {
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// namedColorValues holds the RGB values of the named colors.
+var namedColorValues = map[string][3]uint8{
+	Black:       {0x00, 0x00, 0x00},
+	DarkBlue:    {0x00, 0x00, 0xaa},
+	DarkGreen:   {0x00, 0xaa, 0x00},
+	DarkAqua:    {0x00, 0xaa, 0xaa},
+	DarkRed:     {0xaa, 0x00, 0x00},
+	DarkPurple:  {0xaa, 0x00, 0xaa},
+	Gold:        {0xff, 0xaa, 0x00},
+	Gray:        {0xaa, 0xaa, 0xaa},
+	DarkGray:    {0x55, 0x55, 0x55},
+	Blue:        {0x55, 0x55, 0xff},
+	Green:       {0x55, 0xff, 0x55},
+	Aqua:        {0x55, 0xff, 0xff},
+	Red:         {0xff, 0x55, 0x55},
+	LightPurple: {0xff, 0x55, 0xff},
+	Yellow:      {0xff, 0xff, 0x55},
+	White:       {0xff, 0xff, 0xff},
+}
+
+// colorRGB returns the RGB value of a named or hex color. False is returned if the color is neither.
+func colorRGB(color string) ([3]uint8, bool) {
+	if isHexColor(color) {
+		n, _ := strconv.ParseUint(color[1:], 16, 32)
+		return [3]uint8{uint8(n >> 16), uint8(n >> 8), uint8(n)}, true
+	}
+	rgb, ok := namedColorValues[color]
+	return rgb, ok
+}
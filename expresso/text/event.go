@@ -0,0 +1,191 @@
+package text
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"strconv"
+)
+
+// ClickEvent is an event that occurs when a text component is clicked.
+type ClickEvent struct {
+	// Action is the action performed when the component is clicked.
+	Action ClickAction `json:"action"`
+	// Value is the value of the action, such as the command or URL.
+	Value string `json:"value"`
+}
+
+// ClickAction is the action of a ClickEvent.
+type ClickAction string
+
+const (
+	// ClickOpenURL opens the URL in the value of the event in the browser of the client.
+	ClickOpenURL ClickAction = "open_url"
+	// ClickRunCommand makes the client send the chat message or command in the value of the event.
+	ClickRunCommand ClickAction = "run_command"
+	// ClickSuggestCommand replaces the content of the chat box of the client with the value of the event.
+	ClickSuggestCommand ClickAction = "suggest_command"
+	// ClickChangePage changes the page of the book the component is in to the page in the value of the event.
+	ClickChangePage ClickAction = "change_page"
+	// ClickCopyToClipboard copies the value of the event to the clipboard of the client.
+	ClickCopyToClipboard ClickAction = "copy_to_clipboard"
+)
+
+// OpenURL returns a click event that opens the URL passed.
+func OpenURL(url string) *ClickEvent {
+	return &ClickEvent{Action: ClickOpenURL, Value: url}
+}
+
+// RunCommand returns a click event that runs the command passed, such as /spawn.
+func RunCommand(command string) *ClickEvent {
+	return &ClickEvent{Action: ClickRunCommand, Value: command}
+}
+
+// SuggestCommand returns a click event that puts the command passed in the chat box of the client.
+func SuggestCommand(command string) *ClickEvent {
+	return &ClickEvent{Action: ClickSuggestCommand, Value: command}
+}
+
+// ChangePage returns a click event that changes the page of a book to the page passed.
+func ChangePage(page int) *ClickEvent {
+	return &ClickEvent{Action: ClickChangePage, Value: strconv.Itoa(page)}
+}
+
+// CopyToClipboard returns a click event that copies the text passed to the clipboard of the client.
+func CopyToClipboard(s string) *ClickEvent {
+	return &ClickEvent{Action: ClickCopyToClipboard, Value: s}
+}
+
+// HoverEvent is an event that occurs when a text component is hovered over. Exactly one of Text, Item, Entity
+// and Value is set, depending on the action.
+type HoverEvent struct {
+	// Action is the action performed when the component is hovered over.
+	Action HoverAction
+	// Text is the text shown if the action is HoverShowText.
+	Text *Text
+	// Item is the item shown if the action is HoverShowItem.
+	Item *HoverItem
+	// Entity is the entity shown if the action is HoverShowEntity.
+	Entity *HoverEntity
+	// Value is the legacy value of item and entity events that have no contents, as sent by servers older than
+	// 1.16. It holds SNBT wrapped in a text component, which is not decoded but kept, so that the event is
+	// encoded again unchanged.
+	Value json.RawMessage
+}
+
+// HoverAction is the action of a HoverEvent.
+type HoverAction string
+
+const (
+	// HoverShowText shows a text component.
+	HoverShowText HoverAction = "show_text"
+	// HoverShowItem shows the tooltip of an item.
+	HoverShowItem HoverAction = "show_item"
+	// HoverShowEntity shows the type, UUID and name of an entity.
+	HoverShowEntity HoverAction = "show_entity"
+)
+
+// HoverItem is an item shown by a HoverEvent.
+type HoverItem struct {
+	// ID is the identifier of the item, such as minecraft:diamond_sword.
+	ID string `json:"id"`
+	// Count is the amount of items in the stack.
+	Count int32 `json:"count,omitempty"`
+	// Tag is the NBT of the item, encoded as SNBT.
+	Tag string `json:"tag,omitempty"`
+}
+
+// HoverEntity is an entity shown by a HoverEvent.
+type HoverEntity struct {
+	// Type is the identifier of the entity type, such as minecraft:pig.
+	Type string `json:"type"`
+	// ID is the UUID of the entity.
+	ID uuid.UUID `json:"id"`
+	// Name is the name of the entity. It is left out if nil.
+	Name *Text `json:"name,omitempty"`
+}
+
+// ShowText returns a hover event that shows the text passed.
+func ShowText(t Text) *HoverEvent {
+	return &HoverEvent{Action: HoverShowText, Text: &t}
+}
+
+// ShowItem returns a hover event that shows the tooltip of the item passed.
+func ShowItem(item HoverItem) *HoverEvent {
+	return &HoverEvent{Action: HoverShowItem, Item: &item}
+}
+
+// ShowEntity returns a hover event that shows the entity passed.
+func ShowEntity(entity HoverEntity) *HoverEvent {
+	return &HoverEvent{Action: HoverShowEntity, Entity: &entity}
+}
+
+// hoverEventData is the JSON representation of a HoverEvent.
+type hoverEventData struct {
+	Action   HoverAction     `json:"action"`
+	Contents json.RawMessage `json:"contents,omitempty"`
+	// Value is the content of the event before 1.16, which is still accepted by the client.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// MarshalJSON ...
+func (h HoverEvent) MarshalJSON() ([]byte, error) {
+	if h.Value != nil && ((h.Action == HoverShowItem && h.Item == nil) || (h.Action == HoverShowEntity && h.Entity == nil)) {
+		return json.Marshal(hoverEventData{Action: h.Action, Value: h.Value})
+	}
+	var contents interface{}
+	switch h.Action {
+	case HoverShowText:
+		contents = h.Text
+	case HoverShowItem:
+		contents = h.Item
+	case HoverShowEntity:
+		contents = h.Entity
+	default:
+		return nil, fmt.Errorf("unknown hover event action %q", h.Action)
+	}
+	data, err := json.Marshal(contents)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(hoverEventData{Action: h.Action, Contents: data})
+}
+
+// UnmarshalJSON ...
+func (h *HoverEvent) UnmarshalJSON(b []byte) error {
+	var data hoverEventData
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	*h = HoverEvent{Action: data.Action}
+	contents := data.Contents
+	if contents == nil {
+		if data.Action == HoverShowItem || data.Action == HoverShowEntity {
+			// The legacy value of items and entities is SNBT, which is kept as is.
+			if data.Value == nil {
+				return fmt.Errorf("hover event %q has no contents", data.Action)
+			}
+			h.Value = data.Value
+			return nil
+		}
+		contents = data.Value
+	}
+	switch data.Action {
+	case HoverShowText:
+		h.Text = &Text{}
+		return json.Unmarshal(contents, h.Text)
+	case HoverShowItem:
+		// The contents of an item may also be just its identifier.
+		var id string
+		if json.Unmarshal(contents, &id) == nil {
+			h.Item = &HoverItem{ID: id, Count: 1}
+			return nil
+		}
+		h.Item = &HoverItem{}
+		return json.Unmarshal(contents, h.Item)
+	case HoverShowEntity:
+		h.Entity = &HoverEntity{}
+		return json.Unmarshal(contents, h.Entity)
+	}
+	return fmt.Errorf("unknown hover event action %q", data.Action)
+}
@@ -0,0 +1,122 @@
+package text
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// textData is the JSON representation of a component object. It has the fields of Text, but not its methods,
+// so that it may be encoded and decoded by the encoding/json package.
+type textData Text
+
+// MarshalJSON encodes the component as a JSON object. Components without content are encoded with empty text,
+// as the client requires every component to have content. An error is returned if the color of the component
+// or one of its children is invalid.
+func (t Text) MarshalJSON() ([]byte, error) {
+	if err := validateColor(t.Color); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(textData(t))
+	if err != nil {
+		return nil, err
+	}
+	b = t.appendDisabled(b)
+	if t.hasContent() {
+		return b, nil
+	}
+	if len(b) == 2 {
+		return []byte(`{"text":""}`), nil
+	}
+	return append([]byte(`{"text":"",`), b[1:]...), nil
+}
+
+// UnmarshalJSON decodes a component in any of the forms accepted by the client: A JSON object, a string
+// holding literal text, a number or bool, which is used as literal text, or an array of components, of which
+// the first holds the others as children. An error is returned if the color of a component is neither a named
+// color nor a hex color.
+func (t *Text) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return fmt.Errorf("empty text component")
+	}
+	switch b[0] {
+	case 'n':
+		// JSON null leaves the component unchanged, as with other types.
+		return nil
+	case '"':
+		*t = Text{}
+		return json.Unmarshal(b, &t.Text)
+	case '[':
+		var components []Text
+		if err := json.Unmarshal(b, &components); err != nil {
+			return err
+		}
+		if len(components) == 0 {
+			return fmt.Errorf("empty array of text components")
+		}
+		*t = components[0]
+		t.Extra = append(t.Extra, components[1:]...)
+		return nil
+	case '{':
+		var data textData
+		if err := json.Unmarshal(b, &data); err != nil {
+			return err
+		}
+		data.Color = strings.ToLower(data.Color)
+		if err := validateColor(data.Color); err != nil {
+			return err
+		}
+		var set map[string]json.RawMessage
+		if err := json.Unmarshal(b, &set); err != nil {
+			return err
+		}
+		for _, d := range decorations {
+			if v, ok := set[d.name]; ok && string(bytes.TrimSpace(v)) == "false" {
+				data.Disabled |= d.d
+			}
+		}
+		*t = Text(data)
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch v.(type) {
+	case float64, bool:
+		*t = Text{Text: string(b)}
+		return nil
+	}
+	return fmt.Errorf("invalid text component %s", b)
+}
+
+// appendDisabled adds the decorations disabled in the component, which are not held by the JSON object passed,
+// to the object as false.
+func (t Text) appendDisabled(b []byte) []byte {
+	for _, d := range decorations {
+		if enabled, set := t.Decoration(d.d); set && !enabled {
+			if len(b) == 2 {
+				b = append(b[:1], `"`+d.name+`":false}`...)
+			} else {
+				b = append(b[:len(b)-1], `,"`+d.name+`":false}`...)
+			}
+		}
+	}
+	return b
+}
+
+// hasContent checks if the component has content of any kind.
+func (t Text) hasContent() bool {
+	return t.Text != "" || t.Translate != "" || t.Score != nil || t.Selector != "" || t.Keybind != "" || t.NBT != ""
+}
+
+// validateColor returns an error if the color passed is not empty, a named color, Reset or a hex color of the
+// form #rrggbb.
+func validateColor(color string) error {
+	if _, ok := colorRGB(color); ok || color == "" || color == Reset {
+		return nil
+	}
+	return fmt.Errorf("invalid text color %q", color)
+}
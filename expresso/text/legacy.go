@@ -0,0 +1,255 @@
+package text
+
+import (
+	"strings"
+)
+
+// SectionSign is the character that precedes legacy formatting codes, such as §a.
+const SectionSign = '§'
+
+// legacyColors maps the legacy color codes to the named colors they represent.
+var legacyColors = map[rune]string{
+	'0': Black,
+	'1': DarkBlue,
+	'2': DarkGreen,
+	'3': DarkAqua,
+	'4': DarkRed,
+	'5': DarkPurple,
+	'6': Gold,
+	'7': Gray,
+	'8': DarkGray,
+	'9': Blue,
+	'a': Green,
+	'b': Aqua,
+	'c': Red,
+	'd': LightPurple,
+	'e': Yellow,
+	'f': White,
+}
+
+// FromLegacy parses text in the legacy format, in which formatting codes are preceded by the code character
+// passed, which is usually SectionSign or &. Like in vanilla, a color code resets all formatting before it,
+// and §r resets both the color and formatting. Hex colors are written as §x followed by the six digits of the
+// color, each preceded by the code character, such as §x§f§f§a§a§0§0. Code characters that do not precede a
+// valid code are kept as they are.
+func FromLegacy(s string, code rune) Text {
+	var (
+		parts   []Text
+		style   textStyle
+		current strings.Builder
+	)
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		parts = append(parts, style.text(current.String()))
+		current.Reset()
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != code || i+1 == len(runes) {
+			current.WriteRune(runes[i])
+			continue
+		}
+		c := toLowerASCII(runes[i+1])
+		if color, ok := legacyColors[c]; ok {
+			flush()
+			style = textStyle{color: color}
+			i++
+			continue
+		}
+		switch c {
+		case 'x':
+			hex, ok := legacyHex(runes[i+2:], code)
+			if !ok {
+				current.WriteRune(runes[i])
+				continue
+			}
+			flush()
+			style = textStyle{color: hex}
+			i += 13
+		case 'r':
+			flush()
+			style = textStyle{}
+			i++
+		case 'k', 'l', 'm', 'n', 'o':
+			flush()
+			style.set(c)
+			i++
+		default:
+			current.WriteRune(runes[i])
+		}
+	}
+	flush()
+
+	switch len(parts) {
+	case 0:
+		return Text{}
+	case 1:
+		return parts[0]
+	}
+	return Text{Extra: parts}
+}
+
+// Legacy returns the text in the legacy format using the section sign as code character. Translations are
+// resolved using English. Styles that cannot be represented in the legacy format, such as click events and
+// fonts, are lost.
+func (t Text) Legacy() string {
+	var (
+		b       strings.Builder
+		emitted textStyle
+	)
+	t.walk(English, textStyle{}, func(content string, style textStyle) {
+		if content == "" {
+			return
+		}
+		if style != emitted {
+			if style.color != emitted.color || !emitted.subsetOf(style) {
+				// Both a color code and a reset clear all formatting, so the formatting is written again.
+				if style.color == "" {
+					b.WriteRune(SectionSign)
+					b.WriteByte('r')
+				} else {
+					writeLegacyColor(&b, style.color)
+				}
+				emitted = textStyle{color: style.color}
+			}
+			writeLegacyFormatting(&b, emitted, style)
+			emitted = style
+		}
+		b.WriteString(content)
+	})
+	return b.String()
+}
+
+// text returns a text component with the literal text passed and the style.
+func (s textStyle) text(content string) Text {
+	t := Text{Text: content, Color: s.color}
+	if s.bold {
+		t.Bold = true
+	}
+	if s.italic {
+		t.Italic = true
+	}
+	if s.underlined {
+		t.UnderLined = true
+	}
+	if s.strikethrough {
+		t.StrikeThrough = true
+	}
+	if s.obfuscated {
+		t.Obfuscated = true
+	}
+	return t
+}
+
+// set enables the formatting of the legacy formatting code passed.
+func (s *textStyle) set(code rune) {
+	switch code {
+	case 'k':
+		s.obfuscated = true
+	case 'l':
+		s.bold = true
+	case 'm':
+		s.strikethrough = true
+	case 'n':
+		s.underlined = true
+	case 'o':
+		s.italic = true
+	}
+}
+
+// subsetOf checks if all formatting enabled in the style is also enabled in the style passed.
+func (s textStyle) subsetOf(o textStyle) bool {
+	return (!s.bold || o.bold) && (!s.italic || o.italic) && (!s.underlined || o.underlined) &&
+		(!s.strikethrough || o.strikethrough) && (!s.obfuscated || o.obfuscated)
+}
+
+// writeLegacyColor writes the legacy code of the color passed. Hex colors are written as §x followed by their
+// digits. Unknown colors are written as a reset.
+func writeLegacyColor(b *strings.Builder, color string) {
+	if isHexColor(color) {
+		b.WriteRune(SectionSign)
+		b.WriteByte('x')
+		for _, c := range strings.ToLower(color[1:]) {
+			b.WriteRune(SectionSign)
+			b.WriteRune(c)
+		}
+		return
+	}
+	for code, name := range legacyColors {
+		if name == color {
+			b.WriteRune(SectionSign)
+			b.WriteRune(code)
+			return
+		}
+	}
+	b.WriteRune(SectionSign)
+	b.WriteByte('r')
+}
+
+// writeLegacyFormatting writes the legacy codes of the formatting that is enabled in the style next but not in
+// the style prev.
+func writeLegacyFormatting(b *strings.Builder, prev, next textStyle) {
+	codes := []struct {
+		prev, next bool
+		code       byte
+	}{
+		{prev.obfuscated, next.obfuscated, 'k'},
+		{prev.bold, next.bold, 'l'},
+		{prev.strikethrough, next.strikethrough, 'm'},
+		{prev.underlined, next.underlined, 'n'},
+		{prev.italic, next.italic, 'o'},
+	}
+	for _, c := range codes {
+		if c.next && !c.prev {
+			b.WriteRune(SectionSign)
+			b.WriteByte(c.code)
+		}
+	}
+}
+
+// legacyHex parses the six digits of a legacy hex color, each preceded by the code character passed, from the
+// start of the runes passed.
+func legacyHex(runes []rune, code rune) (string, bool) {
+	if len(runes) < 12 {
+		return "", false
+	}
+	hex := make([]rune, 1, 7)
+	hex[0] = '#'
+	for i := 0; i < 12; i += 2 {
+		c := toLowerASCII(runes[i+1])
+		if runes[i] != code || !isHexDigit(c) {
+			return "", false
+		}
+		hex = append(hex, c)
+	}
+	return string(hex), true
+}
+
+// isHexColor checks if the color passed is a hex color of the form #rrggbb.
+func isHexColor(color string) bool {
+	if len(color) != 7 || color[0] != '#' {
+		return false
+	}
+	for _, c := range color[1:] {
+		if !isHexDigit(toLowerASCII(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHexDigit checks if the lowercase rune passed is a hexadecimal digit.
+func isHexDigit(c rune) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')
+}
+
+// toLowerASCII returns the lowercase variant of ASCII letters, and other runes as they are.
+func toLowerASCII(c rune) rune {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
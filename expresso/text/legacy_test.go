@@ -0,0 +1,76 @@
+package text
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestFromLegacy tests that color codes reset formatting, that formatting is inherited until the next color or
+// reset, that hex colors are parsed and that invalid codes are kept as they are.
+func TestFromLegacy(t *testing.T) {
+	for _, test := range []struct {
+		s        string
+		code     rune
+		expected Text
+	}{
+		{"", SectionSign, Text{}},
+		{"Hello", SectionSign, Text{Text: "Hello"}},
+		{"§aHello", SectionSign, Text{Text: "Hello", Color: Green}},
+		{"§A§LHello", SectionSign, Text{Text: "Hello", Color: Green, Bold: true}},
+		{"&6Gold &lbold", '&', Text{Extra: []Text{
+			{Text: "Gold ", Color: Gold},
+			{Text: "bold", Color: Gold, Bold: true},
+		}}},
+		{"§l§obold§cred", SectionSign, Text{Extra: []Text{
+			{Text: "bold", Bold: true, Italic: true},
+			{Text: "red", Color: Red},
+		}}},
+		{"§c§nred§rplain", SectionSign, Text{Extra: []Text{
+			{Text: "red", Color: Red, UnderLined: true},
+			{Text: "plain"},
+		}}},
+		{"§x§f§f§A§a§0§0§khex", SectionSign, Text{Text: "hex", Color: "#ffaa00", Obfuscated: true}},
+		{"§x§f§fhex", SectionSign, Text{Extra: []Text{{Text: "§x"}, {Text: "hex", Color: White}}}},
+		{"§z100%§", SectionSign, Text{Text: "§z100%§"}},
+		{"&aMOTD §b", '&', Text{Text: "MOTD §b", Color: Green}},
+	} {
+		if text := FromLegacy(test.s, test.code); !reflect.DeepEqual(text, test.expected) {
+			t.Errorf("%q: expected %#v, got %#v", test.s, test.expected, text)
+		}
+	}
+}
+
+// TestLegacy tests that text is written in the legacy format with the fewest codes needed, and that the colors
+// and formatting are written again after a reset.
+func TestLegacy(t *testing.T) {
+	for _, test := range []struct {
+		text     Text
+		expected string
+	}{
+		{Text{Text: "Hello"}, "Hello"},
+		{Text{Text: "Hello", Color: Green, Bold: true}, "§a§lHello"},
+		{Text{Text: "hex", Color: "#FFAA00"}, "§x§f§f§a§a§0§0hex"},
+		{Text{Text: "a", Color: Red, Extra: []Text{{Text: "b", Bold: true}, {Text: "c"}}}, "§ca§lb§cc"},
+		{Text{Text: "a", Bold: true, Extra: []Text{{Text: "b", Color: Gold}}}, "§la§6§lb"},
+		{Text{Text: "a", Color: Red, Extra: []Text{{Text: "b", Color: Reset}}}, "§ca§rb"},
+		{Text{Text: "a", Italic: true, Extra: []Text{{Text: "b", Disabled: DecorationItalic}}}, "§oa§rb"},
+		{Text{Text: "a", Color: "unknown"}, "§ra"},
+	} {
+		if s := test.text.Legacy(); s != test.expected {
+			t.Errorf("%#v: expected %q, got %q", test.text, test.expected, s)
+		}
+	}
+}
+
+// TestLegacyRoundTrip tests that legacy text written by Legacy is parsed to text with the same legacy format.
+func TestLegacyRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"§a§lWelcome §rto §x§1§2§3§4§5§6§n§oexpresso",
+		"§6Gold §l§mbold §7gray",
+		"plain §kmagic",
+	} {
+		if legacy := FromLegacy(s, SectionSign).Legacy(); legacy != s {
+			t.Errorf("%q: got %q after round trip", s, legacy)
+		}
+	}
+}
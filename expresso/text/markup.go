@@ -0,0 +1,523 @@
+package text
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ParseMarkup parses text written in a tag based markup similar to MiniMessage, such as:
+//
+//	<gold><bold>Welcome</bold> <click:run_command:/help>help</click>
+//
+// The following tags are supported, where arguments are separated by colons and may be quoted using single or
+// double quotes:
+//
+//	<gold>, <#ff5555>, <color:gold>        color of the text, also <colour> and <c>, where <color:reset> resets
+//	                                       the color to the default color of the client
+//	<bold>, <italic>, <underlined>,        decorations, also <b>, <i>, <em>, <u>, <st> and <obf>, which are
+//	<strikethrough>, <obfuscated>          turned off explicitly when prefixed with an !, such as <!italic>
+//	<click:action:value>                   click event with an action such as run_command or open_url
+//	<hover:show_text:'text'>               hover event showing the markup passed
+//	<insert:text>                          text inserted into the chat box when shift-clicked
+//	<font:minecraft:uniform>               font of the text
+//	<gradient:color:color...>              gradient of the colors passed over the characters of the text
+//	<reset>                                closes all open tags
+//	<newline>, <br>                        a line break
+//	<key:key.jump>                         the key bound to a keybind
+//	<lang:key:'arg'...>                    translation of the key, with the markup arguments passed
+//	<selector:@p>, <score:name:objective>  names of entities and the score of an entity
+//
+// Tags other than those above are looked up in the placeholders passed, which may be nil, and replaced by the
+// component found. Tags that are unknown are kept as text, and < may be escaped as \<. Closing tags, such as
+// </bold>, close the innermost open tag of the same kind, and tags left open are closed at the end of the text.
+func ParseMarkup(s string, placeholders map[string]Text) (Text, error) {
+	p := &markupParser{placeholders: placeholders, stack: []*markupNode{{}}}
+	if err := p.parse([]rune(s)); err != nil {
+		return Text{}, err
+	}
+	for len(p.stack) > 1 {
+		p.pop()
+	}
+	root := p.stack[0].finish()
+	switch {
+	case len(root.Extra) == 1 && root.Text == "":
+		return root.Extra[0], nil
+	case len(root.Extra) == 0:
+		return Text{Text: root.Text}, nil
+	}
+	return root, nil
+}
+
+// Markup returns the text in the markup parsed by ParseMarkup. Gradients are written as the colors of the
+// separate characters, and hover events that do not show text and NBT components are lost.
+func (t Text) Markup() string {
+	var b strings.Builder
+	t.writeMarkup(&b)
+	return b.String()
+}
+
+// writeMarkup writes the markup of the component and its children to the builder passed.
+func (t Text) writeMarkup(b *strings.Builder) {
+	var closing []string
+	open := func(name string, args ...string) {
+		b.WriteByte('<')
+		b.WriteString(name)
+		for _, arg := range args {
+			b.WriteByte(':')
+			b.WriteString(quoteMarkupArg(arg))
+		}
+		b.WriteByte('>')
+		closing = append(closing, strings.TrimPrefix(name, "!"))
+	}
+
+	if t.Color != "" {
+		if _, ok := namedColorValues[t.Color]; ok || isHexColor(t.Color) {
+			open(t.Color)
+		} else {
+			open("color", t.Color)
+		}
+	}
+	for _, d := range decorations {
+		if enabled, set := t.Decoration(d.d); set {
+			if enabled {
+				open(d.name)
+			} else {
+				open("!" + d.name)
+			}
+		}
+	}
+	if t.Font != "" {
+		open("font", t.Font)
+	}
+	if t.Insertion != "" {
+		open("insert", t.Insertion)
+	}
+	if t.ClickEvent != nil {
+		open("click", string(t.ClickEvent.Action), t.ClickEvent.Value)
+	}
+	if t.HoverEvent != nil && t.HoverEvent.Action == HoverShowText && t.HoverEvent.Text != nil {
+		open("hover", string(HoverShowText), t.HoverEvent.Text.Markup())
+	}
+
+	switch {
+	case t.Translate != "":
+		args := []string{t.Translate}
+		for _, arg := range t.Args() {
+			args = append(args, arg.Markup())
+		}
+		writeMarkupTag(b, "lang", args...)
+	case t.Keybind != "":
+		writeMarkupTag(b, "key", t.Keybind)
+	case t.Score != nil:
+		writeMarkupTag(b, "score", t.Score.Name, t.Score.Objective)
+	case t.Selector != "":
+		writeMarkupTag(b, "selector", t.Selector)
+	default:
+		b.WriteString(escapeMarkup(t.Text))
+	}
+	for _, extra := range t.Extra {
+		extra.writeMarkup(b)
+	}
+	for i := len(closing) - 1; i >= 0; i-- {
+		b.WriteString("</" + closing[i] + ">")
+	}
+}
+
+// writeMarkupTag writes a self-closing tag with the name and arguments passed.
+func writeMarkupTag(b *strings.Builder, name string, args ...string) {
+	b.WriteByte('<')
+	b.WriteString(name)
+	for _, arg := range args {
+		b.WriteByte(':')
+		b.WriteString(quoteMarkupArg(arg))
+	}
+	b.WriteByte('>')
+}
+
+// escapeMarkup escapes the characters of the literal text passed that have a meaning in markup.
+func escapeMarkup(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `<`, `\<`).Replace(s)
+}
+
+// quoteMarkupArg quotes a tag argument if it holds characters that have a meaning in a tag.
+func quoteMarkupArg(s string) string {
+	if s != "" && !strings.ContainsAny(s, `:<>'"\ `) {
+		return s
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// markupAliases maps the aliases of markup tags to the name of the tag.
+var markupAliases = map[string]string{
+	"colour":    "color",
+	"c":         "color",
+	"b":         "bold",
+	"i":         "italic",
+	"em":        "italic",
+	"u":         "underlined",
+	"st":        "strikethrough",
+	"obf":       "obfuscated",
+	"insertion": "insert",
+	"br":        "newline",
+	"tr":        "lang",
+	"translate": "lang",
+	"sel":       "selector",
+}
+
+// markupTagName returns the name of the tag with the name or alias passed. Named and hex colors are tags named
+// color.
+func markupTagName(name string) string {
+	name = strings.TrimPrefix(strings.ToLower(name), "!")
+	if _, ok := namedColorValues[name]; ok || isHexColor(name) {
+		return "color"
+	}
+	if alias, ok := markupAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// markupNode is a tag that is open while parsing markup. The component of the node holds the style of the tag,
+// and the components inside the tag are added to its children.
+type markupNode struct {
+	name     string
+	t        Text
+	gradient [][3]uint8
+}
+
+// finish returns the component of the node after applying its gradient. The children of the component are
+// simplified, and if the first child only holds literal text, the text is moved into the component.
+func (n *markupNode) finish() Text {
+	t := n.t
+	if len(n.gradient) != 0 {
+		applyGradient(&t, n.gradient, countGradientRunes(t))
+	}
+	// Children without content or style of their own are replaced by their children.
+	var extra []Text
+	for _, child := range t.Extra {
+		if reflect.DeepEqual(child, Text{Extra: child.Extra}) {
+			extra = append(extra, child.Extra...)
+			continue
+		}
+		extra = append(extra, child)
+	}
+	t.Extra = extra
+	if len(t.Extra) != 0 && t.content() == "" && t.Score == nil && reflect.DeepEqual(t.Extra[0], Text{Text: t.Extra[0].Text}) {
+		t.Text = t.Extra[0].Text
+		t.Extra = t.Extra[1:]
+	}
+	if len(t.Extra) == 0 {
+		t.Extra = nil
+	}
+	return t
+}
+
+// markupParser parses markup into text components.
+type markupParser struct {
+	placeholders map[string]Text
+	stack        []*markupNode
+	literal      strings.Builder
+}
+
+// parse parses the markup passed.
+func (p *markupParser) parse(runes []rune) error {
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) && (runes[i+1] == '<' || runes[i+1] == '\\') {
+				i++
+			}
+			p.literal.WriteRune(runes[i])
+		case '<':
+			args, end, ok := parseMarkupTag(runes, i)
+			if !ok {
+				p.literal.WriteRune(runes[i])
+				continue
+			}
+			handled, err := p.tag(args)
+			if err != nil {
+				return fmt.Errorf("tag %v: %w", string(runes[i:end+1]), err)
+			}
+			if !handled {
+				p.literal.WriteString(string(runes[i : end+1]))
+			}
+			i = end
+		default:
+			p.literal.WriteRune(runes[i])
+		}
+	}
+	p.flush()
+	return nil
+}
+
+// parseMarkupTag parses the arguments of the tag starting at the offset passed, and returns them along with the
+// offset of the closing >. False is returned if the tag is not closed or an unquoted < is found.
+func parseMarkupTag(runes []rune, start int) (args []string, end int, ok bool) {
+	var arg strings.Builder
+	argStart := true
+	for i := start + 1; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case argStart && (c == '\'' || c == '"'):
+			j := i + 1
+			for ; j < len(runes) && runes[j] != c; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) && (runes[j+1] == c || runes[j+1] == '\\') {
+					j++
+				}
+				arg.WriteRune(runes[j])
+			}
+			if j+1 >= len(runes) || (runes[j+1] != ':' && runes[j+1] != '>') {
+				return nil, 0, false
+			}
+			i = j
+			argStart = false
+		case c == ':':
+			args = append(args, arg.String())
+			arg.Reset()
+			argStart = true
+		case c == '>':
+			args = append(args, arg.String())
+			if args[0] == "" || args[0] == "/" {
+				return nil, 0, false
+			}
+			return args, i, true
+		case c == '<':
+			return nil, 0, false
+		default:
+			arg.WriteRune(c)
+			argStart = false
+		}
+	}
+	return nil, 0, false
+}
+
+// flush adds the literal text read since the last tag to the innermost open tag.
+func (p *markupParser) flush() {
+	if p.literal.Len() == 0 {
+		return
+	}
+	p.add(Text{Text: p.literal.String()})
+	p.literal.Reset()
+}
+
+// add adds a component to the innermost open tag.
+func (p *markupParser) add(t Text) {
+	top := p.stack[len(p.stack)-1]
+	top.t.Extra = append(top.t.Extra, t)
+}
+
+// push opens the tag passed.
+func (p *markupParser) push(n *markupNode) {
+	p.flush()
+	p.stack = append(p.stack, n)
+}
+
+// pop closes the innermost open tag.
+func (p *markupParser) pop() {
+	p.flush()
+	n := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	p.add(n.finish())
+}
+
+// tag handles the tag with the arguments passed. False is returned if the tag is unknown.
+func (p *markupParser) tag(args []string) (bool, error) {
+	if strings.HasPrefix(args[0], "/") {
+		name := markupTagName(args[0][1:])
+		for i := len(p.stack) - 1; i > 0; i-- {
+			if p.stack[i].name == name {
+				for len(p.stack) > i {
+					p.pop()
+				}
+				return true, nil
+			}
+		}
+		// Closing tags of tags that are not open are ignored.
+		return true, nil
+	}
+	if t, ok := p.placeholders[args[0]]; ok {
+		p.flush()
+		p.add(t)
+		return true, nil
+	}
+
+	raw := strings.ToLower(args[0])
+	name := markupTagName(raw)
+	n := &markupNode{name: name}
+	if strings.HasPrefix(raw, "!") {
+		switch name {
+		case "bold", "italic", "underlined", "strikethrough", "obfuscated":
+		default:
+			return false, nil
+		}
+	}
+	switch name {
+	case "color":
+		color := raw
+		if markupAliases[raw] == "color" || raw == "color" {
+			if len(args) != 2 {
+				return false, fmt.Errorf("expected a color")
+			}
+			color = strings.ToLower(args[1])
+			if _, ok := colorRGB(color); !ok && color != Reset {
+				return false, fmt.Errorf("unknown color %q", args[1])
+			}
+		}
+		n.t.Color = color
+	case "bold", "italic", "underlined", "strikethrough", "obfuscated":
+		for _, d := range decorations {
+			if d.name == name {
+				n.t.SetDecoration(d.d, !strings.HasPrefix(raw, "!"))
+			}
+		}
+	case "click":
+		if len(args) < 3 {
+			return false, fmt.Errorf("expected an action and a value")
+		}
+		action := ClickAction(strings.ToLower(args[1]))
+		switch action {
+		case ClickOpenURL, ClickRunCommand, ClickSuggestCommand, ClickChangePage, ClickCopyToClipboard:
+		default:
+			return false, fmt.Errorf("unknown click action %q", args[1])
+		}
+		n.t.ClickEvent = &ClickEvent{Action: action, Value: strings.Join(args[2:], ":")}
+	case "hover":
+		if len(args) != 3 || strings.ToLower(args[1]) != string(HoverShowText) {
+			return false, fmt.Errorf("expected show_text and the text to show")
+		}
+		t, err := ParseMarkup(args[2], p.placeholders)
+		if err != nil {
+			return false, err
+		}
+		n.t.HoverEvent = ShowText(t)
+	case "insert":
+		if len(args) < 2 {
+			return false, fmt.Errorf("expected the text to insert")
+		}
+		n.t.Insertion = strings.Join(args[1:], ":")
+	case "font":
+		if len(args) < 2 {
+			return false, fmt.Errorf("expected a font")
+		}
+		n.t.Font = strings.Join(args[1:], ":")
+	case "gradient":
+		for _, arg := range args[1:] {
+			rgb, ok := colorRGB(strings.ToLower(arg))
+			if !ok {
+				return false, fmt.Errorf("unknown color %q", arg)
+			}
+			n.gradient = append(n.gradient, rgb)
+		}
+		if len(n.gradient) == 0 {
+			n.gradient = [][3]uint8{namedColorValues[White], namedColorValues[Black]}
+		}
+	case "reset":
+		for len(p.stack) > 1 {
+			p.pop()
+		}
+		return true, nil
+	case "newline":
+		p.literal.WriteByte('\n')
+		return true, nil
+	case "key":
+		if len(args) != 2 {
+			return false, fmt.Errorf("expected a keybind")
+		}
+		p.flush()
+		p.add(Text{Keybind: args[1]})
+		return true, nil
+	case "lang":
+		if len(args) < 2 {
+			return false, fmt.Errorf("expected a translation key")
+		}
+		var with []Text
+		for _, arg := range args[2:] {
+			w, err := ParseMarkup(arg, p.placeholders)
+			if err != nil {
+				return false, err
+			}
+			with = append(with, w)
+		}
+		t := Text{Translate: args[1], With: encodeArgs(with)}
+		p.flush()
+		p.add(t)
+		return true, nil
+	case "selector":
+		if len(args) != 2 {
+			return false, fmt.Errorf("expected a selector")
+		}
+		p.flush()
+		p.add(Text{Selector: args[1]})
+		return true, nil
+	case "score":
+		if len(args) != 3 {
+			return false, fmt.Errorf("expected a name and an objective")
+		}
+		p.flush()
+		p.add(Text{Score: &Score{Name: args[1], Objective: args[2]}})
+		return true, nil
+	default:
+		return false, nil
+	}
+	p.push(n)
+	return true, nil
+}
+
+// countGradientRunes counts the runes of the literal text of the component and its children that do not have
+// a color of their own.
+func countGradientRunes(t Text) int {
+	n := len([]rune(t.Text))
+	for _, extra := range t.Extra {
+		if extra.Color == "" {
+			n += countGradientRunes(extra)
+		}
+	}
+	return n
+}
+
+// applyGradient splits the literal text of the component and its children without a color of their own into
+// components holding one rune each, colored with the gradient of the colors passed over the total number of
+// runes passed.
+func applyGradient(t *Text, colors [][3]uint8, total int) {
+	index := 0
+	var apply func(t *Text)
+	apply = func(t *Text) {
+		var runes []Text
+		for _, r := range t.Text {
+			runes = append(runes, Text{Text: string(r), Color: gradientColor(colors, index, total)})
+			index++
+		}
+		for i := range t.Extra {
+			if t.Extra[i].Color == "" {
+				apply(&t.Extra[i])
+			}
+		}
+		if len(runes) != 0 {
+			t.Text = ""
+			t.Extra = append(runes, t.Extra...)
+		}
+	}
+	apply(t)
+}
+
+// gradientColor returns the hex color at the index passed of a gradient of the colors passed over a total
+// number of indices.
+func gradientColor(colors [][3]uint8, index, total int) string {
+	if len(colors) == 1 || total <= 1 {
+		c := colors[0]
+		return Hex(c[0], c[1], c[2])
+	}
+	pos := float64(index) / float64(total-1) * float64(len(colors)-1)
+	segment := int(pos)
+	if segment >= len(colors)-1 {
+		segment = len(colors) - 2
+	}
+	frac := pos - float64(segment)
+	from, to := colors[segment], colors[segment+1]
+	var rgb [3]uint8
+	for i := range rgb {
+		rgb[i] = uint8(float64(from[i]) + (float64(to[i])-float64(from[i]))*frac + 0.5)
+	}
+	return Hex(rgb[0], rgb[1], rgb[2])
+}
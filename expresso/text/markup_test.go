@@ -0,0 +1,96 @@
+package text
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseMarkup tests that tags are parsed into the components they describe, that quoted and escaped
+// arguments are unquoted, and that unknown tags and escaped < are kept as text.
+func TestParseMarkup(t *testing.T) {
+	placeholders := map[string]Text{"player": {Text: "Steve", Color: Aqua}}
+	for _, test := range []struct {
+		s        string
+		expected Text
+	}{
+		{"Hello", Text{Text: "Hello"}},
+		{"<gold>Hello", Text{Text: "Hello", Color: Gold}},
+		{"<#FF5555>Hex</#ff5555>", Text{Text: "Hex", Color: "#ff5555"}},
+		{"<color:reset>plain", Text{Text: "plain", Color: Reset}},
+		{"<gold><bold>Welcome</bold> home", Text{Color: Gold, Extra: []Text{{Text: "Welcome", Bold: true}, {Text: " home"}}}},
+		{"<b>bold</b> plain", Text{Extra: []Text{{Text: "bold", Bold: true}, {Text: " plain"}}}},
+		{"<italic><!i>upright", Text{Italic: true, Extra: []Text{{Text: "upright", Disabled: DecorationItalic}}}},
+		{"<click:run_command:/tp 0 0 0>go", Text{Text: "go", ClickEvent: RunCommand("/tp 0 0 0")}},
+		{"<click:open_url:'https://example.com'>site", Text{Text: "site", ClickEvent: OpenURL("https://example.com")}},
+		{`<hover:show_text:'<red>it\'s red'>hover`, Text{Text: "hover", HoverEvent: ShowText(Text{Text: "it's red", Color: Red})}},
+		{`<insert:"a:b">insert`, Text{Text: "insert", Insertion: "a:b"}},
+		{"<font:minecraft:uniform>font", Text{Text: "font", Font: "minecraft:uniform"}},
+		{"<gradient:red:blue>ab</gradient>", Text{Extra: []Text{{Text: "a", Color: "#ff5555"}, {Text: "b", Color: "#5555ff"}}}},
+		{"<gradient:#000000:#ffffff>abc", Text{Extra: []Text{{Text: "a", Color: "#000000"}, {Text: "b", Color: "#808080"}, {Text: "c", Color: "#ffffff"}}}},
+		{"<red>a<reset>b", Text{Extra: []Text{{Text: "a", Color: Red}, {Text: "b"}}}},
+		{"a<br>b", Text{Text: "a\nb"}},
+		{"<key:key.jump>", Text{Keybind: "key.jump"}},
+		{"<lang:chat.type.text:'<player>':hi>", Text{Translate: "chat.type.text", With: encodeArgs([]Text{{Text: "Steve", Color: Aqua}, {Text: "hi"}})}},
+		{"Hi <player>!", Text{Text: "Hi ", Extra: []Text{{Text: "Steve", Color: Aqua}, {Text: "!"}}}},
+		{"<unknown>tag", Text{Text: "<unknown>tag"}},
+		{"<!gold>tag", Text{Text: "<!gold>tag"}},
+		{`\<gold> and \\`, Text{Text: `<gold> and \`}},
+		{"1 < 2 > 0", Text{Text: "1 < 2 > 0"}},
+		{"</gold>closed", Text{Text: "closed"}},
+	} {
+		text, err := ParseMarkup(test.s, placeholders)
+		if err != nil {
+			t.Errorf("%q: %v", test.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(text, test.expected) {
+			t.Errorf("%q: expected %#v, got %#v", test.s, test.expected, text)
+		}
+	}
+
+	for _, s := range []string{
+		"<color:nope>text",
+		"<color>text",
+		"<click:explode:now>text",
+		"<hover:show_item:stone>text",
+		"<gradient:red:nope>text",
+		"<key>",
+	} {
+		if text, err := ParseMarkup(s, nil); err == nil {
+			t.Errorf("%q: expected error, got %#v", s, text)
+		}
+	}
+}
+
+// TestMarkupRoundTrip tests that text written as markup is parsed back to the same text, and that arguments
+// are quoted only when needed.
+func TestMarkupRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		text     Text
+		expected string
+	}{
+		{Text{Text: "plain"}, "plain"},
+		{Text{Text: "<escaped> \\"}, `\<escaped> \\`},
+		{Text{Color: Gold, Extra: []Text{{Text: "gold", Bold: true}}}, "<gold><bold>gold</bold></gold>"},
+		{Text{Text: "reset", Color: Reset}, "<color:reset>reset</color>"},
+		{Text{Italic: true, Extra: []Text{{Text: "upright", Disabled: DecorationItalic}}}, "<italic><!italic>upright</italic></italic>"},
+		{Text{Text: "go", ClickEvent: RunCommand("/tp 0 0 0")}, "<click:run_command:'/tp 0 0 0'>go</click>"},
+		{Text{Text: "it", Insertion: "it's"}, `<insert:'it\'s'>it</insert>`},
+		{Text{Text: "hover", HoverEvent: ShowText(Text{Text: "red", Color: Red})}, "<hover:show_text:'<red>red</red>'>hover</hover>"},
+		{Text{Translate: "chat.type.text", With: encodeArgs([]Text{{Text: "Steve"}, {Text: "hi there"}})}, "<lang:chat.type.text:Steve:'hi there'>"},
+	} {
+		s := test.text.Markup()
+		if s != test.expected {
+			t.Errorf("%#v: expected %q, got %q", test.text, test.expected, s)
+			continue
+		}
+		parsed, err := ParseMarkup(s, nil)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(parsed, test.text) {
+			t.Errorf("%q: expected %#v after round trip, got %#v", s, test.text, parsed)
+		}
+	}
+}
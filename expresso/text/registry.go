@@ -0,0 +1,163 @@
+package text
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Registry holds the languages of a server, so that translatable components may be rendered in the locale of
+// a client, which is sent by the client in its settings. Languages are loaded from files in the format of the
+// vanilla language files, such as en_us.json, and custom translations may be registered on top of them.
+// Translations missing in a language are taken from the fallback language of the registry. A Registry is safe
+// for concurrent use.
+type Registry struct {
+	fallback string
+
+	mu        sync.RWMutex
+	languages map[string]Language
+	merged    map[string]Language
+}
+
+// NewRegistry returns a new Registry that falls back to the locale passed, such as en_us, for translations
+// missing in other languages. The English translations of the package are registered in the fallback
+// language.
+func NewRegistry(fallback string) *Registry {
+	r := &Registry{
+		fallback:  normalizeLocale(fallback),
+		languages: make(map[string]Language),
+		merged:    make(map[string]Language),
+	}
+	r.Add(fallback, English)
+	return r
+}
+
+// Add adds the translations passed to the language of the locale passed, overwriting existing translations
+// of the same keys.
+func (r *Registry) Add(locale string, translations Language) {
+	locale = normalizeLocale(locale)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, ok := r.languages[locale]
+	if !ok {
+		l = make(Language, len(translations))
+		r.languages[locale] = l
+	}
+	for key, format := range translations {
+		l[key] = format
+	}
+	r.merged = make(map[string]Language)
+}
+
+// Register registers a single translation of the key passed in the language of the locale passed.
+func (r *Registry) Register(locale, key, format string) {
+	r.Add(locale, Language{key: format})
+}
+
+// Load loads a language file in the format of the vanilla language files, which is a JSON object mapping
+// translation keys to format strings, from the reader passed and adds its translations to the language of the
+// locale passed.
+func (r *Registry) Load(locale string, reader io.Reader) error {
+	var translations Language
+	if err := json.NewDecoder(reader).Decode(&translations); err != nil {
+		return fmt.Errorf("decode language %v: %w", locale, err)
+	}
+	r.Add(locale, translations)
+	return nil
+}
+
+// LoadFile loads the language file at the path passed using Load. The locale of the language is derived from
+// the name of the file, so that en_us.json holds the language of the locale en_us.
+func (r *Registry) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return r.Load(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), f)
+}
+
+// LoadDir loads all language files with the .json extension in the directory passed using LoadFile.
+func (r *Registry) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := r.LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Locales returns the locales of all languages in the registry.
+func (r *Registry) Locales() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	locales := make([]string, 0, len(r.languages))
+	for locale := range r.languages {
+		locales = append(locales, locale)
+	}
+	return locales
+}
+
+// Language returns the language of the locale passed, including the translations of the fallback language
+// that the language does not have. If the registry has no language for the locale, the fallback language is
+// returned. The Language returned must not be modified.
+func (r *Registry) Language(locale string) Language {
+	locale = normalizeLocale(locale)
+
+	r.mu.RLock()
+	if _, ok := r.languages[locale]; !ok {
+		// Locales are sent by clients, so unknown locales share the fallback language rather than each being
+		// cached separately.
+		locale = r.fallback
+	}
+	l, ok := r.merged[locale]
+	r.mu.RUnlock()
+	if ok {
+		return l
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.languages[locale]; !ok {
+		locale = r.fallback
+	}
+	if l, ok := r.merged[locale]; ok {
+		return l
+	}
+	fallback := r.languages[r.fallback]
+	l = make(Language, len(fallback))
+	for key, format := range fallback {
+		l[key] = format
+	}
+	for key, format := range r.languages[locale] {
+		l[key] = format
+	}
+	r.merged[locale] = l
+	return l
+}
+
+// Render renders the text passed in the language of the locale passed using Language.Render. The locale of a
+// client may be found in the Locale field of its settings.
+func (r *Registry) Render(t Text, locale string) Text {
+	return r.Language(locale).Render(t)
+}
+
+// Plain returns the text passed without formatting in the language of the locale passed using
+// Language.Plain.
+func (r *Registry) Plain(t Text, locale string) string {
+	return r.Language(locale).Plain(t)
+}
+
+// normalizeLocale returns the locale passed in the lowercase form used by the client, such as en_us.
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "-", "_"))
+}
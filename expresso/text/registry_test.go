@@ -0,0 +1,85 @@
+package text
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestRegistryLanguage tests that languages are merged with the fallback language, that locales are
+// normalized, and that unknown locales share the fallback language.
+func TestRegistryLanguage(t *testing.T) {
+	r := NewRegistry("en_US")
+	r.Add("de-DE", Language{"multiplayer.player.left": "%s hat das Spiel verlassen", "custom": "nur Deutsch"})
+	r.Register("en_us", "custom", "English only")
+
+	for _, test := range []struct {
+		locale, key, expected string
+	}{
+		{"de_de", "multiplayer.player.left", "%s hat das Spiel verlassen"},
+		{"DE-de", "custom", "nur Deutsch"},
+		{"de_de", "disconnect.timeout", "Timed out"},
+		{"en_us", "custom", "English only"},
+		{"fr_fr", "custom", "English only"},
+		{"fr_fr", "multiplayer.player.left", "%s left the game"},
+	} {
+		if format := r.Language(test.locale)[test.key]; format != test.expected {
+			t.Errorf("%v %v: expected %q, got %q", test.locale, test.key, test.expected, format)
+		}
+	}
+
+	r.Language("xx_yy")
+	r.Language("zz_zz")
+	if _, ok := r.merged["xx_yy"]; ok {
+		t.Errorf("unknown locale was cached separately")
+	}
+	locales := r.Locales()
+	sort.Strings(locales)
+	if expected := []string{"de_de", "en_us"}; !reflect.DeepEqual(locales, expected) {
+		t.Errorf("expected locales %v, got %v", expected, locales)
+	}
+}
+
+// TestRegistryAdd tests that adding translations invalidates the merged languages, including those of
+// languages that only take the translations from the fallback language.
+func TestRegistryAdd(t *testing.T) {
+	r := NewRegistry("en_us")
+	r.Add("de_de", Language{"a": "A"})
+	if format := r.Language("de_de")["b"]; format != "" {
+		t.Fatalf("expected no translation of b, got %q", format)
+	}
+	r.Register("en_us", "b", "B")
+	r.Register("de_de", "a", "Ä")
+	l := r.Language("de_de")
+	if l["a"] != "Ä" || l["b"] != "B" {
+		t.Errorf("expected merged translations Ä and B, got %q and %q", l["a"], l["b"])
+	}
+	if s := r.Plain(Text{Translate: "a"}, "de_de"); s != "Ä" {
+		t.Errorf("expected plain text Ä, got %q", s)
+	}
+}
+
+// TestRegistryLoad tests that language files are loaded with the locale taken from their name, and that
+// invalid files are rejected.
+func TestRegistryLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "nl_nl.json"), []byte(`{"disconnect.timeout":"Time-out"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a language"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRegistry("en_us")
+	if err := r.LoadDir(dir); err != nil {
+		t.Fatalf("load directory: %v", err)
+	}
+	if s := r.Plain(Text{Translate: "disconnect.timeout"}, "nl_NL"); s != "Time-out" {
+		t.Errorf("expected Time-out, got %q", s)
+	}
+	if err := r.Load("xx_xx", strings.NewReader(`{"a":1}`)); err == nil {
+		t.Errorf("expected error loading invalid language")
+	}
+}
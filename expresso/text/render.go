@@ -0,0 +1,127 @@
+package text
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Plain returns the text without any formatting, with the content of all children flattened and translations
+// resolved using English.
+func (t Text) Plain() string {
+	return English.Plain(t)
+}
+
+// ANSI returns the text formatted using ANSI escape codes with 24-bit colors, so that it may be written to a
+// terminal. Translations are resolved using English.
+func (t Text) ANSI() string {
+	return English.ANSI(t)
+}
+
+// Plain returns the text passed without any formatting, with the content of all children flattened and
+// translations resolved using the language.
+func (l Language) Plain(t Text) string {
+	var b strings.Builder
+	t.walk(l, textStyle{}, func(content string, _ textStyle) {
+		b.WriteString(content)
+	})
+	return b.String()
+}
+
+// ANSI returns the text passed formatted using ANSI escape codes with 24-bit colors, with translations resolved
+// using the language. Obfuscated text is written as is.
+func (l Language) ANSI(t Text) string {
+	var (
+		b       strings.Builder
+		emitted textStyle
+	)
+	t.walk(l, textStyle{}, func(content string, style textStyle) {
+		if content == "" {
+			return
+		}
+		if style != emitted {
+			b.WriteString("\x1b[0")
+			if rgb, ok := colorRGB(style.color); ok {
+				b.WriteString(";38;2;" + strconv.Itoa(int(rgb[0])) + ";" + strconv.Itoa(int(rgb[1])) + ";" + strconv.Itoa(int(rgb[2])))
+			}
+			if style.bold {
+				b.WriteString(";1")
+			}
+			if style.italic {
+				b.WriteString(";3")
+			}
+			if style.underlined {
+				b.WriteString(";4")
+			}
+			if style.strikethrough {
+				b.WriteString(";9")
+			}
+			b.WriteByte('m')
+			emitted = style
+		}
+		b.WriteString(content)
+	})
+	if emitted != (textStyle{}) {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// textStyle is the style of text after inheriting the style of its parents.
+type textStyle struct {
+	color                                               string
+	bold, italic, underlined, strikethrough, obfuscated bool
+}
+
+// walk calls the function passed for the content of the component and all of its children, along with the
+// style the content is displayed with, which is the style of the component inherited from the parent style
+// passed. If the language passed is not nil, translations are resolved using it.
+func (t Text) walk(l Language, parent textStyle, f func(content string, style textStyle)) {
+	style := parent
+	if t.Color != "" {
+		style.color = t.Color
+		if t.Color == Reset {
+			style.color = ""
+		}
+	}
+	t.setDecoration(&style.bold, DecorationBold)
+	t.setDecoration(&style.italic, DecorationItalic)
+	t.setDecoration(&style.underlined, DecorationUnderlined)
+	t.setDecoration(&style.strikethrough, DecorationStrikethrough)
+	t.setDecoration(&style.obfuscated, DecorationObfuscated)
+
+	if t.Translate != "" && l != nil {
+		for _, part := range l.translate(t.Translate, t.Args()) {
+			part.walk(l, style, f)
+		}
+	} else {
+		f(t.content(), style)
+	}
+	for _, extra := range t.Extra {
+		extra.walk(l, style, f)
+	}
+}
+
+// content returns the content of the component as it is displayed without a client, excluding the children of
+// the component.
+func (t Text) content() string {
+	switch {
+	case t.Translate != "":
+		return t.Translate
+	case t.Keybind != "":
+		return t.Keybind
+	case t.Score != nil:
+		return t.Score.Value
+	case t.Selector != "":
+		return t.Selector
+	case t.NBT != "":
+		return t.NBT
+	}
+	return t.Text
+}
+
+// setDecoration sets the bool passed to whether the decoration passed is enabled, if the component sets it.
+func (t Text) setDecoration(b *bool, d Decoration) {
+	if enabled, set := t.Decoration(d); set {
+		*b = enabled
+	}
+}
@@ -0,0 +1,42 @@
+package text
+
+import (
+	"testing"
+)
+
+// TestPlain tests that the content of children is flattened and that translations are resolved.
+func TestPlain(t *testing.T) {
+	for _, test := range []struct {
+		text     Text
+		expected string
+	}{
+		{Text{Text: "Hello", Color: Gold}, "Hello"},
+		{Text{Text: "a", Extra: []Text{{Text: "b", Extra: []Text{{Text: "c"}}}, {Text: "d"}}}, "abcd"},
+		{Text{Translate: "chat.type.text", With: encodeArgs([]Text{{Text: "Steve"}, {Text: "hi", Extra: []Text{{Text: "!"}}}})}, "<Steve> hi!"},
+		{Text{Translate: "unknown.key"}, "unknown.key"},
+		{Text{Keybind: "key.jump"}, "key.jump"},
+		{Text{Score: &Score{Name: "Steve", Objective: "kills", Value: "3"}}, "3"},
+	} {
+		if s := test.text.Plain(); s != test.expected {
+			t.Errorf("%#v: expected %q, got %q", test.text, test.expected, s)
+		}
+	}
+}
+
+// TestANSI tests that styles are written as 24-bit ANSI escape codes only when they change, and that the
+// style is reset at the end of the text.
+func TestANSI(t *testing.T) {
+	for _, test := range []struct {
+		text     Text
+		expected string
+	}{
+		{Text{Text: "plain"}, "plain"},
+		{Text{Text: "gold", Color: Gold}, "\x1b[0;38;2;255;170;0mgold\x1b[0m"},
+		{Text{Text: "a", Bold: true, Extra: []Text{{Text: "b"}, {Text: "c", Color: "#010203", Italic: true}}}, "\x1b[0;1ma" + "b" + "\x1b[0;38;2;1;2;3;1;3mc\x1b[0m"},
+		{Text{Text: "u", UnderLined: true, StrikeThrough: true, Extra: []Text{{Text: "p", Color: Reset, Disabled: DecorationUnderlined | DecorationStrikethrough}}}, "\x1b[0;4;9mu\x1b[0mp"},
+	} {
+		if s := test.text.ANSI(); s != test.expected {
+			t.Errorf("%#v: expected %q, got %q", test.text, test.expected, s)
+		}
+	}
+}
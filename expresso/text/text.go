@@ -1,20 +1,173 @@
 package text
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
-// Text represents the custom JSON text format in Minecraft.
-// TODO: Add options to make creating this type of text simple.
+// Text represents the custom JSON text format in Minecraft. A text component has exactly one kind of content:
+// Literal text, a translation, a score, an entity selector, a keybind or an NBT value. The content is followed
+// by the components in Extra, which inherit the style of the component.
+//
+// Text components are most easily created using a Builder, which is returned by New and the other builder
+// functions of this package.
 type Text struct {
+	// Text is the literal text of the component.
 	Text string `json:"text,omitempty"`
 
-	Bold          bool   `json:"bold,omitempty"`
-	Italic        bool   `json:"italic,omitempty"`
-	UnderLined    bool   `json:"underlined,omitempty"`
-	StrikeThrough bool   `json:"strikethrough,omitempty"`
-	Obfuscated    bool   `json:"obfuscated,omitempty"`
-	Color         string `json:"color,omitempty"`
-
+	// Translate is the translation key of a translatable component, such as chat.type.text. The arguments of
+	// the translation are held by With, each encoded as a text component. Args returns them decoded.
 	Translate string            `json:"translate,omitempty"`
 	With      []json.RawMessage `json:"with,omitempty"`
-	Extra     []Text            `json:"extra,omitempty"`
+
+	// Score is the score of an entity that is displayed by a score component.
+	Score *Score `json:"score,omitempty"`
+	// Selector is an entity selector, such as @p, of which the names of the matched entities are displayed by a
+	// selector component. The names are separated by Separator, or by a comma if it is nil.
+	Selector string `json:"selector,omitempty"`
+	// Separator is the separator used between the values of selector and NBT components.
+	Separator *Text `json:"separator,omitempty"`
+	// Keybind is the identifier of a keybind, such as key.jump, of which the key bound by the client is
+	// displayed by a keybind component.
+	Keybind string `json:"keybind,omitempty"`
+
+	// NBT is the NBT path of the value displayed by an NBT component. The value is taken from the block entity
+	// at Block, the entities matched by the selector Entity, or the command storage Storage.
+	NBT     string `json:"nbt,omitempty"`
+	Block   string `json:"block,omitempty"`
+	Entity  string `json:"entity,omitempty"`
+	Storage string `json:"storage,omitempty"`
+	// Interpret specifies if the NBT value of an NBT component should be interpreted as a text component.
+	Interpret bool `json:"interpret,omitempty"`
+
+	// Color is the color of the text. It is either the name of a color, such as gold, or a hex color of the
+	// form #rrggbb.
+	Color string `json:"color,omitempty"`
+	// Font is the resource location of the font of the text, such as minecraft:uniform.
+	Font string `json:"font,omitempty"`
+
+	// Bold, Italic, UnderLined, StrikeThrough and Obfuscated enable the decorations of the text. Decorations
+	// that are not enabled are inherited from the parent component, unless they are disabled in Disabled.
+	Bold          bool `json:"bold,omitempty"`
+	Italic        bool `json:"italic,omitempty"`
+	UnderLined    bool `json:"underlined,omitempty"`
+	StrikeThrough bool `json:"strikethrough,omitempty"`
+	Obfuscated    bool `json:"obfuscated,omitempty"`
+	// Disabled holds the decorations that are explicitly turned off, so that they are not inherited from the
+	// parent component. A decoration that is both enabled and disabled is enabled.
+	Disabled Decoration `json:"-"`
+
+	// Insertion is text inserted into the chat box of the client when the component is shift-clicked.
+	Insertion string `json:"insertion,omitempty"`
+	// ClickEvent is the event that occurs when the component is clicked in chat or in a book.
+	ClickEvent *ClickEvent `json:"clickEvent,omitempty"`
+	// HoverEvent is the event that occurs when the component is hovered over.
+	HoverEvent *HoverEvent `json:"hoverEvent,omitempty"`
+
+	// Extra holds the components that follow this component. They inherit its style.
+	Extra []Text `json:"extra,omitempty"`
+}
+
+// Score is the content of a score component.
+type Score struct {
+	// Name is the name of the entity holding the score, or a selector matching a single entity. If it is *,
+	// the score of the player reading the text is displayed.
+	Name string `json:"name"`
+	// Objective is the name of the scoreboard objective of the score.
+	Objective string `json:"objective"`
+	// Value is the value displayed, which, if set, is displayed instead of the actual score.
+	Value string `json:"value,omitempty"`
+}
+
+// Decoration is a decoration of text, such as bold. Decorations may be combined using a bitwise or.
+type Decoration uint8
+
+// The decorations that text may have.
+const (
+	DecorationBold Decoration = 1 << iota
+	DecorationItalic
+	DecorationUnderlined
+	DecorationStrikethrough
+	DecorationObfuscated
+)
+
+// decorations holds all decorations along with their names in the JSON format, in the order they are written.
+var decorations = []struct {
+	d    Decoration
+	name string
+}{
+	{DecorationBold, "bold"},
+	{DecorationItalic, "italic"},
+	{DecorationUnderlined, "underlined"},
+	{DecorationStrikethrough, "strikethrough"},
+	{DecorationObfuscated, "obfuscated"},
+}
+
+// Decoration returns whether the decoration passed is enabled in the component, and whether the component
+// sets it at all. If it is not set, it is inherited from the parent component.
+func (t Text) Decoration(d Decoration) (enabled, set bool) {
+	if *t.decorationField(d) {
+		return true, true
+	}
+	return false, t.Disabled&d != 0
+}
+
+// SetDecoration enables or disables the decoration passed in the component, overriding the decoration of the
+// parent component.
+func (t *Text) SetDecoration(d Decoration, enabled bool) {
+	*t.decorationField(d) = enabled
+	if enabled {
+		t.Disabled &^= d
+	} else {
+		t.Disabled |= d
+	}
+}
+
+// decorationField returns a pointer to the field enabling the decoration passed.
+func (t *Text) decorationField(d Decoration) *bool {
+	switch d {
+	case DecorationBold:
+		return &t.Bold
+	case DecorationItalic:
+		return &t.Italic
+	case DecorationUnderlined:
+		return &t.UnderLined
+	case DecorationStrikethrough:
+		return &t.StrikeThrough
+	case DecorationObfuscated:
+		return &t.Obfuscated
+	}
+	panic(fmt.Errorf("invalid text decoration %v", d))
+}
+
+// Args returns the arguments of a translatable component held by With. Arguments that cannot be decoded are
+// returned as literal text holding their JSON.
+func (t Text) Args() []Text {
+	if len(t.With) == 0 {
+		return nil
+	}
+	args := make([]Text, len(t.With))
+	for i, raw := range t.With {
+		if err := json.Unmarshal(raw, &args[i]); err != nil {
+			args[i] = Text{Text: string(raw)}
+		}
+	}
+	return args
+}
+
+// encodeArgs encodes the arguments of a translatable component for use in With. Arguments that cannot be
+// encoded, such as ones with an invalid color, are encoded as their plain text.
+func encodeArgs(args []Text) []json.RawMessage {
+	if len(args) == 0 {
+		return nil
+	}
+	with := make([]json.RawMessage, len(args))
+	for i, arg := range args {
+		b, err := json.Marshal(arg)
+		if err != nil {
+			b, _ = json.Marshal(arg.Plain())
+		}
+		with[i] = b
+	}
+	return with
 }
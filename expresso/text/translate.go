@@ -0,0 +1,140 @@
+package text
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Language is a table of translations of a single locale. It maps translation keys, such as chat.type.text,
+// to format strings, such as <%s> %s, in which %s is replaced by the next argument of a translatable
+// component, %1$s by the first argument and %% by a percent sign.
+type Language map[string]string
+
+// English holds the English translations of the keys used by the server, which are used to resolve
+// translations if no other language is specified.
+var English = Language{
+	"chat.type.text":                         "<%s> %s",
+	"chat.type.announcement":                 "[%s] %s",
+	"chat.type.emote":                        "* %s %s",
+	"chat.type.admin":                        "[%s: %s]",
+	"commands.message.display.incoming":      "%s whispers to you: %s",
+	"commands.message.display.outgoing":      "You whisper to %s: %s",
+	"multiplayer.player.joined":              "%s joined the game",
+	"multiplayer.player.joined.renamed":      "%s (formerly known as %s) joined the game",
+	"multiplayer.player.left":                "%s left the game",
+	"multiplayer.disconnect.kicked":          "Kicked by an operator",
+	"multiplayer.disconnect.server_shutdown": "Server closed",
+	"multiplayer.disconnect.outdated_client": "Incompatible client! Please use %s",
+	"multiplayer.disconnect.outdated_server": "Incompatible client! Please use %s",
+	"disconnect.timeout":                     "Timed out",
+	"death.attack.generic":                   "%s died",
+}
+
+// Translate returns the translation of the key passed with the arguments passed substituted in it. If the
+// language has no translation for the key, the key itself is returned.
+func (l Language) Translate(key string, args ...string) string {
+	texts := make([]Text, len(args))
+	for i, arg := range args {
+		texts[i] = Text{Text: arg}
+	}
+	var b strings.Builder
+	for _, part := range l.translate(key, texts) {
+		b.WriteString(part.Text)
+	}
+	return b.String()
+}
+
+// translate returns the parts of the translation of the key passed: Literal text components holding the text
+// between the arguments, and the arguments passed themselves. If the language has no translation for the key,
+// a component holding the key is returned. Arguments that are missing are left out.
+func (l Language) translate(key string, args []Text) []Text {
+	format, ok := l[key]
+	if !ok {
+		return []Text{{Text: key}}
+	}
+	var (
+		parts   []Text
+		literal strings.Builder
+		next    int
+	)
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			literal.WriteByte(format[i])
+			continue
+		}
+		// Parse the optional explicit index of the argument, such as the 1$ in %1$s.
+		j, index := i+1, -1
+		for j < len(format) && format[j] >= '0' && format[j] <= '9' {
+			j++
+		}
+		if j > i+1 && j+1 < len(format) && format[j] == '$' {
+			index, _ = strconv.Atoi(format[i+1 : j])
+			index--
+			j++
+		} else {
+			j = i + 1
+		}
+		switch format[j] {
+		case '%':
+			literal.WriteByte('%')
+		case 's', 'd':
+			if index == -1 {
+				index = next
+				next++
+			}
+			if literal.Len() != 0 {
+				parts = append(parts, Text{Text: literal.String()})
+				literal.Reset()
+			}
+			if index >= 0 && index < len(args) {
+				parts = append(parts, args[index])
+			}
+		default:
+			literal.WriteString(format[i : j+1])
+		}
+		i = j
+	}
+	if literal.Len() != 0 {
+		parts = append(parts, Text{Text: literal.String()})
+	}
+	return parts
+}
+
+// Render returns the text passed with all translatable components that the language has a translation for
+// replaced by components holding the translation, with their arguments substituted and rendered recursively.
+// Translatable components with keys the language does not know are left for the client to translate. The
+// style of the components is kept.
+func (l Language) Render(t Text) Text {
+	args := t.Args()
+	with := make([]Text, len(args))
+	for i, arg := range args {
+		with[i] = l.Render(arg)
+	}
+	extra := make([]Text, len(t.Extra))
+	for i, e := range t.Extra {
+		extra[i] = l.Render(e)
+	}
+	if t.Separator != nil {
+		separator := l.Render(*t.Separator)
+		t.Separator = &separator
+	}
+	if t.HoverEvent != nil && t.HoverEvent.Text != nil {
+		hover := *t.HoverEvent
+		hoverText := l.Render(*hover.Text)
+		hover.Text = &hoverText
+		t.HoverEvent = &hover
+	}
+
+	if _, ok := l[t.Translate]; ok && t.Translate != "" {
+		extra = append(l.translate(t.Translate, with), extra...)
+		t.Translate, with = "", nil
+	}
+	t.With, t.Extra = nil, nil
+	if len(with) != 0 {
+		t.With = encodeArgs(with)
+	}
+	if len(extra) != 0 {
+		t.Extra = extra
+	}
+	return t
+}
@@ -0,0 +1,65 @@
+package text
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestTranslate tests that sequential and explicitly indexed arguments are substituted in translations, that
+// %% is written as a percent sign, and that missing arguments and unknown keys are handled.
+func TestTranslate(t *testing.T) {
+	l := Language{
+		"seq":     "<%s> %s",
+		"indexed": "%2$s before %1$s, then %s",
+		"percent": "100%% of %d",
+		"invalid": "50% %q %",
+		"missing": "%s and %s and %3$s",
+	}
+	for _, test := range []struct {
+		key      string
+		args     []string
+		expected string
+	}{
+		{"seq", []string{"Steve", "hi"}, "<Steve> hi"},
+		{"indexed", []string{"a", "b"}, "b before a, then a"},
+		{"percent", []string{"5"}, "100% of 5"},
+		{"invalid", nil, "50% %q %"},
+		{"missing", []string{"a"}, "a and  and "},
+		{"unknown.key", []string{"a"}, "unknown.key"},
+	} {
+		if s := l.Translate(test.key, test.args...); s != test.expected {
+			t.Errorf("%v %v: expected %q, got %q", test.key, test.args, test.expected, s)
+		}
+	}
+}
+
+// TestRender tests that translatable components are replaced by their translation with their arguments
+// rendered recursively, and that components with unknown keys are left as they are.
+func TestRender(t *testing.T) {
+	for _, test := range []struct {
+		text     Text
+		expected Text
+	}{
+		{Text{Text: "plain"}, Text{Text: "plain"}},
+		{
+			Text{Translate: "chat.type.text", With: encodeArgs([]Text{{Text: "Steve", Color: Aqua}, {Text: "hi"}}), Color: Gray},
+			Text{Color: Gray, Extra: []Text{{Text: "<"}, {Text: "Steve", Color: Aqua}, {Text: "> "}, {Text: "hi"}}},
+		},
+		{
+			Text{Translate: "multiplayer.player.left", With: encodeArgs([]Text{{Translate: "death.attack.generic", With: encodeArgs([]Text{{Text: "Alex"}})}})},
+			Text{Extra: []Text{{Extra: []Text{{Text: "Alex"}, {Text: " died"}}}, {Text: " left the game"}}},
+		},
+		{
+			Text{Translate: "unknown.key", With: encodeArgs([]Text{{Translate: "disconnect.timeout"}})},
+			Text{Translate: "unknown.key", With: encodeArgs([]Text{{Extra: []Text{{Text: "Timed out"}}}})},
+		},
+		{
+			Text{Text: "a", Extra: []Text{{Translate: "disconnect.timeout", Bold: true}}},
+			Text{Text: "a", Extra: []Text{{Bold: true, Extra: []Text{{Text: "Timed out"}}}}},
+		},
+	} {
+		if rendered := English.Render(test.text); !reflect.DeepEqual(rendered, test.expected) {
+			t.Errorf("%#v: expected %#v, got %#v", test.text, test.expected, rendered)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package world
+
+import (
+	"github.com/justtaldevelops/expresso/expresso/protocol"
+	"strings"
+)
+
+// defaultDimensionCodec is the vanilla dimension codec, used to look up the IDs of vanilla biomes.
+var defaultDimensionCodec = protocol.DefaultDimensionCodec()
+
+// plainsBiome is the ID of the plains biome, which generators use by default.
+var plainsBiome, _ = BiomeID("minecraft:plains")
+
+// BiomeID returns the ID of the vanilla biome with the name passed, such as minecraft:plains. Names without a
+// namespace are assumed to be in the minecraft namespace. False is returned if the biome does not exist.
+func BiomeID(name string) (int32, bool) {
+	if !strings.Contains(name, ":") {
+		name = "minecraft:" + name
+	}
+	_, id, ok := defaultDimensionCodec.Biome(name)
+	return id, ok
+}
@@ -0,0 +1,202 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BlockRegistry maps block state names, such as minecraft:grass_block[snowy=false], to the block state IDs used
+// in chunks and back.
+type BlockRegistry interface {
+	// StateID returns the block state ID of the block state name passed. If the name has no properties, the
+	// default state of the block is returned. False is returned if the block state is unknown.
+	StateID(name string) (int32, bool)
+	// StateName returns the block state name of the block state ID passed. False is returned if the block
+	// state is unknown.
+	StateName(id int32) (string, bool)
+}
+
+// MapBlockRegistry is a BlockRegistry backed by maps. It is safe for concurrent use.
+type MapBlockRegistry struct {
+	mu sync.RWMutex
+
+	stateIDs   map[string]int32
+	stateNames map[int32]string
+	defaults   map[string]int32
+}
+
+// NewMapBlockRegistry returns a new empty MapBlockRegistry.
+func NewMapBlockRegistry() *MapBlockRegistry {
+	return &MapBlockRegistry{
+		stateIDs:   make(map[string]int32),
+		stateNames: make(map[int32]string),
+		defaults:   make(map[string]int32),
+	}
+}
+
+// LoadBlockRegistry loads a MapBlockRegistry from the blocks.json report produced by the vanilla data
+// generator, which holds every block state of the game.
+func LoadBlockRegistry(r io.Reader) (*MapBlockRegistry, error) {
+	var report map[string]struct {
+		States []struct {
+			ID         int32             `json:"id"`
+			Default    bool              `json:"default"`
+			Properties map[string]string `json:"properties"`
+		} `json:"states"`
+	}
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("decode block report: %w", err)
+	}
+
+	registry := NewMapBlockRegistry()
+	for name, block := range report {
+		for _, state := range block.States {
+			registry.Register(name, state.Properties, state.ID, state.Default)
+		}
+	}
+	return registry, nil
+}
+
+// Register registers a block state with the block name, properties and ID passed. If def is true, the state
+// is used when the block name is looked up without properties.
+func (m *MapBlockRegistry) Register(name string, properties map[string]string, id int32, def bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stateName := name + encodeProperties(properties)
+	m.stateIDs[stateName] = id
+	m.stateNames[id] = stateName
+	if _, ok := m.defaults[name]; def || !ok {
+		m.defaults[name] = id
+	}
+}
+
+// StateID ...
+func (m *MapBlockRegistry) StateID(name string) (int32, bool) {
+	name, properties, err := ParseStateName(name)
+	if err != nil {
+		return 0, false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(properties) == 0 {
+		id, ok := m.defaults[name]
+		return id, ok
+	}
+	id, ok := m.stateIDs[name+encodeProperties(properties)]
+	return id, ok
+}
+
+// StateName ...
+func (m *MapBlockRegistry) StateName(id int32) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name, ok := m.stateNames[id]
+	return name, ok
+}
+
+// ParseStateName parses a block state name such as minecraft:oak_log[axis=y] into the block name and its
+// properties. Names without a namespace are assumed to be in the minecraft namespace.
+func ParseStateName(s string) (string, map[string]string, error) {
+	s = strings.TrimSpace(s)
+	name, props := s, ""
+	if i := strings.IndexByte(s, '['); i != -1 {
+		if !strings.HasSuffix(s, "]") {
+			return "", nil, fmt.Errorf("block state %v has unterminated properties", s)
+		}
+		name, props = s[:i], s[i+1:len(s)-1]
+	}
+	if name == "" {
+		return "", nil, fmt.Errorf("block state %q has no name", s)
+	}
+	if !strings.Contains(name, ":") {
+		name = "minecraft:" + name
+	}
+
+	properties := make(map[string]string)
+	if props != "" {
+		for _, prop := range strings.Split(props, ",") {
+			kv := strings.SplitN(prop, "=", 2)
+			if len(kv) != 2 {
+				return "", nil, fmt.Errorf("block state %v has invalid property %v", s, prop)
+			}
+			properties[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	return name, properties, nil
+}
+
+// EncodeStateName encodes a block name and its properties to a canonical block state name, with the properties
+// sorted by key, such as minecraft:oak_log[axis=y]. It is the inverse of ParseStateName.
+func EncodeStateName(name string, properties map[string]string) string {
+	return name + encodeProperties(properties)
+}
+
+// encodeProperties encodes block state properties to their canonical form, sorted by key, such as
+// [axis=y,waterlogged=false]. An empty string is returned if there are no properties.
+func encodeProperties(properties map[string]string) string {
+	if len(properties) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+properties[k])
+	}
+	return "[" + strings.Join(pairs, ",") + "]"
+}
+
+// defaultBlockRegistry is the registry returned by DefaultBlockRegistry.
+var defaultBlockRegistry = NewMapBlockRegistry()
+
+// init registers the default states of common blocks for the current version in the default block registry.
+func init() {
+	for name, id := range map[string]int32{
+		"minecraft:air":               0,
+		"minecraft:stone":             1,
+		"minecraft:granite":           2,
+		"minecraft:polished_granite":  3,
+		"minecraft:diorite":           4,
+		"minecraft:polished_diorite":  5,
+		"minecraft:andesite":          6,
+		"minecraft:polished_andesite": 7,
+		"minecraft:dirt":              10,
+		"minecraft:coarse_dirt":       11,
+		"minecraft:cobblestone":       14,
+		"minecraft:oak_planks":        15,
+		"minecraft:bedrock":           33,
+		"minecraft:sand":              66,
+		"minecraft:red_sand":          67,
+		"minecraft:gravel":            68,
+	} {
+		defaultBlockRegistry.Register(name, nil, id, true)
+	}
+	defaultBlockRegistry.Register("minecraft:grass_block", map[string]string{"snowy": "true"}, 8, false)
+	defaultBlockRegistry.Register("minecraft:grass_block", map[string]string{"snowy": "false"}, 9, true)
+	defaultBlockRegistry.Register("minecraft:podzol", map[string]string{"snowy": "true"}, 12, false)
+	defaultBlockRegistry.Register("minecraft:podzol", map[string]string{"snowy": "false"}, 13, true)
+	for level := int32(0); level < 16; level++ {
+		properties := map[string]string{"level": fmt.Sprint(level)}
+		defaultBlockRegistry.Register("minecraft:water", properties, 34+level, level == 0)
+		defaultBlockRegistry.Register("minecraft:lava", properties, 50+level, level == 0)
+	}
+}
+
+// DefaultBlockRegistry returns a registry holding the states of a small set of common blocks for the current
+// version, enough for simple terrain. For the complete set of block states, use LoadBlockRegistry with the
+// report of the vanilla data generator.
+func DefaultBlockRegistry() *MapBlockRegistry {
+	return defaultBlockRegistry
+}
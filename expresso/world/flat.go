@@ -0,0 +1,95 @@
+package world
+
+import (
+	"fmt"
+	"github.com/justtaldevelops/expresso/expresso/protocol"
+	"strconv"
+	"strings"
+)
+
+// FlatLayer is a single layer of a superflat world.
+type FlatLayer struct {
+	// State is the block state ID the layer is made of.
+	State int32
+	// Height is the number of blocks the layer is high.
+	Height int32
+}
+
+// FlatGenerator is a Generator that generates superflat terrain, made up of layers of blocks that are the same
+// in every column.
+type FlatGenerator struct {
+	// Layers contains the layers of the world, ordered from the bottom to the top.
+	Layers []FlatLayer
+	// Biome is the ID of the biome used in every column.
+	Biome int32
+	// MinY and Height are the min_y and height of the dimension type the world is in. The first layer starts at
+	// MinY. If Height is zero, columns range from Y zero to Y 255.
+	MinY, Height int32
+}
+
+// DefaultFlatPreset is the preset of the classic superflat world.
+const DefaultFlatPreset = "minecraft:bedrock,2*minecraft:dirt,minecraft:grass_block;minecraft:plains"
+
+// ParseFlatPreset parses a superflat preset in the vanilla syntax, such as
+// "minecraft:bedrock,2*minecraft:dirt,minecraft:grass_block;minecraft:plains", into a FlatGenerator. Block
+// names are resolved to block states using the registry passed. The structure options that may follow the
+// biome are ignored.
+func ParseFlatPreset(preset string, blocks BlockRegistry) (*FlatGenerator, error) {
+	parts := strings.Split(strings.TrimSpace(preset), ";")
+	if len(parts) > 1 {
+		if _, err := strconv.Atoi(parts[0]); err == nil {
+			// Older presets are prefixed with a version number, which we have no use for.
+			parts = parts[1:]
+		}
+	}
+
+	g := &FlatGenerator{Biome: plainsBiome}
+	if parts[0] != "" {
+		for _, layer := range strings.Split(parts[0], ",") {
+			name, height := layer, int32(1)
+			if i := strings.IndexByte(layer, '*'); i != -1 && !strings.Contains(layer[:i], ":") {
+				n, err := strconv.Atoi(strings.TrimSpace(layer[:i]))
+				if err != nil || n < 1 {
+					return nil, fmt.Errorf("invalid height in flat layer %v", layer)
+				}
+				name, height = layer[i+1:], int32(n)
+			}
+			state, ok := blocks.StateID(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown block %v in flat layer %v", name, layer)
+			}
+			g.Layers = append(g.Layers, FlatLayer{State: state, Height: height})
+		}
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		biome, ok := BiomeID(parts[1])
+		if n, err := strconv.Atoi(parts[1]); err == nil {
+			// Older presets refer to biomes by their numeric ID.
+			biome, ok = int32(n), true
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown biome %v in flat preset", parts[1])
+		}
+		g.Biome = biome
+	}
+	return g, nil
+}
+
+// GenerateColumn ...
+func (g *FlatGenerator) GenerateColumn(pos protocol.ColumnPos) *protocol.Column {
+	column := newColumn(pos, g.MinY, g.Height)
+	fillBiomes(column, g.Biome)
+
+	y := column.MinY
+	for _, layer := range g.Layers {
+		for i := int32(0); i < layer.Height; i++ {
+			for x := int32(0); x < 16; x++ {
+				for z := int32(0); z < 16; z++ {
+					_ = column.SetBlockState(protocol.BlockPos{x, y, z}, layer.State)
+				}
+			}
+			y++
+		}
+	}
+	return column
+}
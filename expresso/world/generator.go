@@ -0,0 +1,25 @@
+package world
+
+import "github.com/justtaldevelops/expresso/expresso/protocol"
+
+// Generator generates the terrain of columns that do not exist yet.
+type Generator interface {
+	// GenerateColumn generates a new column at the position passed.
+	GenerateColumn(pos protocol.ColumnPos) *protocol.Column
+}
+
+// fillBiomes sets every biome of the column passed to the biome ID passed.
+func fillBiomes(column *protocol.Column, biome int32) {
+	for i := range column.Biomes {
+		column.Biomes[i] = biome
+	}
+}
+
+// newColumn creates a new empty column at the position passed, with the minimum Y and height passed. If the
+// height is zero, the column ranges from Y zero to Y 255.
+func newColumn(pos protocol.ColumnPos, minY, height int32) *protocol.Column {
+	if height == 0 {
+		return protocol.NewColumn(pos)
+	}
+	return protocol.NewColumnWithHeight(pos, minY, height)
+}
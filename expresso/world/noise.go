@@ -0,0 +1,172 @@
+package world
+
+import (
+	"github.com/justtaldevelops/expresso/expresso/protocol"
+	"math"
+	"math/rand"
+)
+
+// NoiseGenerator is a Generator that generates rolling hills using seeded Perlin noise. The same seed always
+// results in the same terrain.
+type NoiseGenerator struct {
+	// BaseHeight is the average height of the terrain.
+	BaseHeight int32
+	// Amplitude is the maximum number of blocks the terrain deviates from the base height.
+	Amplitude float64
+	// Scale is the horizontal size of the features of the terrain, in blocks. Larger values result in
+	// smoother terrain.
+	Scale float64
+	// Octaves is the number of layers of noise combined. More octaves result in more detailed terrain.
+	Octaves int
+	// SeaLevel is the height up to which empty space below it is filled with water.
+	SeaLevel int32
+
+	// Bedrock, Stone, Dirt, Grass, Sand and Water are the block states used to generate the terrain.
+	Bedrock, Stone, Dirt, Grass, Sand, Water int32
+	// Biome is the ID of the biome used in every column.
+	Biome int32
+	// MinY and Height are the min_y and height of the dimension type the world is in. Bedrock is generated at
+	// MinY. If Height is zero, columns range from Y zero to Y 255.
+	MinY, Height int32
+
+	perm [512]int32
+}
+
+// NewNoiseGenerator returns a new NoiseGenerator with the seed passed, using default settings and block
+// states of the default block registry.
+func NewNoiseGenerator(seed int64) *NoiseGenerator {
+	blocks := DefaultBlockRegistry()
+	state := func(name string) int32 {
+		id, _ := blocks.StateID(name)
+		return id
+	}
+
+	g := &NoiseGenerator{
+		BaseHeight: 64,
+		Amplitude:  24,
+		Scale:      96,
+		Octaves:    4,
+		SeaLevel:   62,
+
+		Bedrock: state("minecraft:bedrock"),
+		Stone:   state("minecraft:stone"),
+		Dirt:    state("minecraft:dirt"),
+		Grass:   state("minecraft:grass_block"),
+		Sand:    state("minecraft:sand"),
+		Water:   state("minecraft:water"),
+		Biome:   plainsBiome,
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	p := r.Perm(256)
+	for i := 0; i < 512; i++ {
+		g.perm[i] = int32(p[i&255])
+	}
+	return g
+}
+
+// GenerateColumn ...
+func (g *NoiseGenerator) GenerateColumn(pos protocol.ColumnPos) *protocol.Column {
+	column := newColumn(pos, g.MinY, g.Height)
+	fillBiomes(column, g.Biome)
+
+	for x := int32(0); x < 16; x++ {
+		for z := int32(0); z < 16; z++ {
+			worldX, worldZ := float64(pos.X()<<4|x), float64(pos.Z()<<4|z)
+			height := g.TerrainHeight(worldX, worldZ)
+
+			for y := column.MinY; y <= column.MaxY() && (y <= height || y <= g.SeaLevel); y++ {
+				_ = column.SetBlockState(protocol.BlockPos{x, y, z}, g.stateAt(y, column.MinY, height))
+			}
+		}
+	}
+	return column
+}
+
+// TerrainHeight returns the height of the terrain at the world X and Z passed.
+func (g *NoiseGenerator) TerrainHeight(x, z float64) int32 {
+	return int32(math.Round(float64(g.BaseHeight) + g.fractal(x/g.Scale, z/g.Scale)*g.Amplitude))
+}
+
+// stateAt returns the block state at the Y passed, in a column with the minimum Y and terrain height passed.
+func (g *NoiseGenerator) stateAt(y, minY, height int32) int32 {
+	switch {
+	case y == minY:
+		return g.Bedrock
+	case y > height:
+		return g.Water
+	case height <= g.SeaLevel+1 && y > height-3:
+		// The terrain is close to the water, so we generate a beach.
+		return g.Sand
+	case y == height:
+		return g.Grass
+	case y > height-4:
+		return g.Dirt
+	}
+	return g.Stone
+}
+
+// fractal returns the sum of multiple octaves of noise at the X and Y passed, in the range -1 to 1.
+func (g *NoiseGenerator) fractal(x, y float64) float64 {
+	octaves := g.Octaves
+	if octaves < 1 {
+		octaves = 1
+	}
+
+	var total, max float64
+	amplitude, frequency := 1.0, 1.0
+	for i := 0; i < octaves; i++ {
+		total += g.noise(x*frequency, y*frequency) * amplitude
+		max += amplitude
+
+		amplitude /= 2
+		frequency *= 2
+	}
+	return total / max
+}
+
+// noise returns two-dimensional Perlin noise at the X and Y passed, roughly in the range -1 to 1.
+func (g *NoiseGenerator) noise(x, y float64) float64 {
+	floorX, floorY := math.Floor(x), math.Floor(y)
+	cellX, cellY := int32(floorX)&255, int32(floorY)&255
+	x, y = x-floorX, y-floorY
+	u, v := fade(x), fade(y)
+
+	a, b := g.perm[cellX]+cellY, g.perm[cellX+1]+cellY
+	return lerp(v,
+		lerp(u, gradient(g.perm[a], x, y), gradient(g.perm[b], x-1, y)),
+		lerp(u, gradient(g.perm[a+1], x, y-1), gradient(g.perm[b+1], x-1, y-1)),
+	)
+}
+
+// fade smooths the interpolation factor passed using the curve 6t^5 - 15t^4 + 10t^3.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// lerp linearly interpolates between a and b using the factor t.
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// gradient returns the dot product of one of eight gradient vectors, selected by the hash passed, and the
+// distance vector passed.
+func gradient(hash int32, x, y float64) float64 {
+	switch hash & 7 {
+	case 0:
+		return x + y
+	case 1:
+		return -x + y
+	case 2:
+		return x - y
+	case 3:
+		return -x - y
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return y
+	}
+	return -y
+}
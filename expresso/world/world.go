@@ -0,0 +1,354 @@
+package world
+
+import (
+	"fmt"
+	"github.com/justtaldevelops/expresso/expresso/protocol"
+	"sync"
+	"time"
+)
+
+// Provider loads and saves the columns of a World, for example from disk or a database.
+type Provider interface {
+	// LoadColumn loads the column at the position passed. If the column has never been saved, false is returned
+	// and the column is generated instead. If true is returned, the column must not be nil.
+	LoadColumn(pos protocol.ColumnPos) (*protocol.Column, bool, error)
+	// SaveColumn saves the column passed, so that it may be loaded again later. The column is locked while it is
+	// saved.
+	SaveColumn(column *protocol.Column) error
+}
+
+// NopProvider is a Provider that never has any columns saved and discards every column saved to it. Worlds with
+// a NopProvider generate every column they load and lose all changes when columns are unloaded.
+type NopProvider struct{}
+
+// LoadColumn ...
+func (NopProvider) LoadColumn(protocol.ColumnPos) (*protocol.Column, bool, error) {
+	return nil, false, nil
+}
+
+// SaveColumn ...
+func (NopProvider) SaveColumn(*protocol.Column) error {
+	return nil
+}
+
+// World owns a set of loaded columns. Columns are loaded lazily through a Provider when they are first used,
+// or generated by a Generator if the provider does not have them. It is safe for concurrent use: The methods
+// of World lock the columns they use, as does sending a column to a connection. Columns are loaded, generated
+// and saved without holding up the use of other columns.
+type World struct {
+	provider  Provider
+	generator Generator
+
+	// mu protects the columns map and the dirty and lastUsed fields of the columns in it. It is never held
+	// while a column is loaded, generated or saved, and is always locked after the lock of a column.
+	mu      sync.Mutex
+	columns map[protocol.ColumnPos]*loadedColumn
+}
+
+// loadedColumn is a column loaded in a World, along with the data needed to decide when to save and unload it.
+type loadedColumn struct {
+	pos protocol.ColumnPos
+	// loaded is closed once the column has been loaded or generated, after which column or err is set.
+	loaded chan struct{}
+	column *protocol.Column
+	err    error
+
+	dirty    bool
+	lastUsed time.Time
+	// unloaded is set, while the column is locked, once the column has been removed from the world.
+	unloaded bool
+}
+
+// New returns a new World that loads columns from the provider passed and generates missing columns using the
+// generator passed. If the provider is nil, a NopProvider is used.
+func New(provider Provider, generator Generator) *World {
+	if provider == nil {
+		provider = NopProvider{}
+	}
+	return &World{
+		provider:  provider,
+		generator: generator,
+		columns:   make(map[protocol.ColumnPos]*loadedColumn),
+	}
+}
+
+// Column returns the column at the position passed, loading or generating it if it is not yet loaded. The
+// column is shared with the world and the connections it is sent to, so callers that read or modify it
+// directly must hold its lock using Column.Lock. Callers that modify it must also call MarkDirty, so that the
+// changes are saved. World implements expresso.ChunkSource through this method.
+func (w *World) Column(pos protocol.ColumnPos) (*protocol.Column, error) {
+	c, err := w.column(pos)
+	if err != nil {
+		return nil, err
+	}
+	return c.column, nil
+}
+
+// Block returns the block state ID at the world position passed.
+func (w *World) Block(pos protocol.BlockPos) (state int32, err error) {
+	err = w.use(columnPosOf(pos), func(c *loadedColumn) error {
+		state, err = c.column.GetBlockState(localPos(pos))
+		return err
+	})
+	return state, err
+}
+
+// SetBlock sets the block state ID at the world position passed and marks its column as dirty.
+func (w *World) SetBlock(pos protocol.BlockPos, state int32) error {
+	return w.use(columnPosOf(pos), func(c *loadedColumn) error {
+		if err := c.column.SetBlockState(localPos(pos), state); err != nil {
+			return err
+		}
+		w.markDirty(c)
+		return nil
+	})
+}
+
+// BlockEntity returns the block entity at the world position passed. False is returned if there is none.
+func (w *World) BlockEntity(pos protocol.BlockPos) (be protocol.BlockEntity, ok bool, err error) {
+	err = w.use(columnPosOf(pos), func(c *loadedColumn) error {
+		be, ok = c.column.BlockEntity(localPos(pos))
+		return nil
+	})
+	return be, ok, err
+}
+
+// SetBlockEntity sets the block entity at the world position passed and marks its column as dirty. If the
+// block entity is nil, any block entity at the position is removed.
+func (w *World) SetBlockEntity(pos protocol.BlockPos, be protocol.BlockEntity) error {
+	return w.use(columnPosOf(pos), func(c *loadedColumn) error {
+		if be == nil {
+			c.column.RemoveBlockEntity(localPos(pos))
+		} else if err := c.column.SetBlockEntity(localPos(pos), be); err != nil {
+			return err
+		}
+		w.markDirty(c)
+		return nil
+	})
+}
+
+// MarkDirty marks the column at the position passed as changed, so that it is saved to the provider when it is
+// unloaded or when Save is called. Nothing happens if the column is not loaded.
+func (w *World) MarkDirty(pos protocol.ColumnPos) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if c, ok := w.columns[pos]; ok {
+		c.dirty = true
+	}
+}
+
+// Loaded returns the positions of all columns currently loaded.
+func (w *World) Loaded() []protocol.ColumnPos {
+	columns := w.loadedColumns()
+	positions := make([]protocol.ColumnPos, 0, len(columns))
+	for _, c := range columns {
+		positions = append(positions, c.pos)
+	}
+	return positions
+}
+
+// Unload saves the column at the position passed if it is dirty and unloads it. Nothing happens if the column
+// is not loaded.
+func (w *World) Unload(pos protocol.ColumnPos) error {
+	w.mu.Lock()
+	c, ok := w.columns[pos]
+	w.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	<-c.loaded
+	if c.err != nil {
+		return nil
+	}
+	return w.unload(c, 0)
+}
+
+// UnloadIdle saves and unloads every column that has not been used for at least the duration passed. It
+// should be called periodically to keep the memory usage of the world bounded.
+func (w *World) UnloadIdle(idle time.Duration) error {
+	for _, c := range w.loadedColumns() {
+		if err := w.unload(c, idle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save saves every dirty column to the provider. The columns stay loaded.
+func (w *World) Save() error {
+	for _, c := range w.loadedColumns() {
+		c.column.Lock()
+		var err error
+		if !c.unloaded {
+			err = w.save(c)
+		}
+		c.column.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close saves every dirty column to the provider and unloads all columns.
+func (w *World) Close() error {
+	for _, c := range w.loadedColumns() {
+		if err := w.unload(c, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// use calls the function passed with the column at the position passed locked, loading or generating the
+// column first if it is not yet loaded.
+func (w *World) use(pos protocol.ColumnPos, f func(c *loadedColumn) error) error {
+	for {
+		c, err := w.column(pos)
+		if err != nil {
+			return err
+		}
+		c.column.Lock()
+		if c.unloaded {
+			// The column was unloaded while waiting for its lock, so it is loaded again.
+			c.column.Unlock()
+			continue
+		}
+		err = f(c)
+		c.column.Unlock()
+		return err
+	}
+}
+
+// column returns the loaded column at the position passed, loading or generating it if needed. If the column
+// is already being loaded, column waits for it to be loaded instead of loading it again.
+func (w *World) column(pos protocol.ColumnPos) (*loadedColumn, error) {
+	w.mu.Lock()
+	c, ok := w.columns[pos]
+	if ok {
+		c.lastUsed = time.Now()
+		w.mu.Unlock()
+		<-c.loaded
+		if c.err != nil {
+			return nil, c.err
+		}
+		return c, nil
+	}
+	c = &loadedColumn{pos: pos, loaded: make(chan struct{}), lastUsed: time.Now()}
+	w.columns[pos] = c
+	w.mu.Unlock()
+
+	column, generated, err := w.load(pos)
+	w.mu.Lock()
+	if err != nil {
+		delete(w.columns, pos)
+	}
+	c.column, c.err, c.dirty = column, err, generated
+	w.mu.Unlock()
+	close(c.loaded)
+
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// load loads the column at the position passed from the provider, or generates it if the provider does not
+// have it, in which case generated is true.
+func (w *World) load(pos protocol.ColumnPos) (column *protocol.Column, generated bool, err error) {
+	column, ok, err := w.provider.LoadColumn(pos)
+	if err != nil {
+		return nil, false, fmt.Errorf("load column %v: %w", pos, err)
+	}
+	if ok {
+		if column == nil {
+			return nil, false, fmt.Errorf("load column %v: provider returned no column", pos)
+		}
+		return column, false, nil
+	}
+	if w.generator == nil {
+		return nil, false, fmt.Errorf("column %v does not exist and world has no generator", pos)
+	}
+	return w.generator.GenerateColumn(pos), true, nil
+}
+
+// loadedColumns returns all columns of the world that were loaded successfully, waiting for columns that are
+// still being loaded.
+func (w *World) loadedColumns() []*loadedColumn {
+	w.mu.Lock()
+	columns := make([]*loadedColumn, 0, len(w.columns))
+	for _, c := range w.columns {
+		columns = append(columns, c)
+	}
+	w.mu.Unlock()
+
+	loaded := columns[:0]
+	for _, c := range columns {
+		<-c.loaded
+		if c.err == nil {
+			loaded = append(loaded, c)
+		}
+	}
+	return loaded
+}
+
+// unload saves the column passed if it is dirty and removes it from the world, unless it was used during the
+// idle duration passed.
+func (w *World) unload(c *loadedColumn, idle time.Duration) error {
+	c.column.Lock()
+	defer c.column.Unlock()
+	if c.unloaded {
+		return nil
+	}
+	w.mu.Lock()
+	used := time.Since(c.lastUsed) < idle
+	w.mu.Unlock()
+	if used {
+		return nil
+	}
+	// The column stays locked until it is removed, so that it is not changed after it was saved.
+	if err := w.save(c); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	if w.columns[c.pos] == c {
+		delete(w.columns, c.pos)
+	}
+	c.unloaded = true
+	w.mu.Unlock()
+	return nil
+}
+
+// save saves the column passed to the provider if it is dirty. The column must be locked.
+func (w *World) save(c *loadedColumn) error {
+	w.mu.Lock()
+	dirty := c.dirty
+	c.dirty = false
+	w.mu.Unlock()
+	if !dirty {
+		return nil
+	}
+	if err := w.provider.SaveColumn(c.column); err != nil {
+		w.markDirty(c)
+		return fmt.Errorf("save column %v: %w", c.pos, err)
+	}
+	return nil
+}
+
+// markDirty marks the column passed as changed.
+func (w *World) markDirty(c *loadedColumn) {
+	w.mu.Lock()
+	c.dirty = true
+	w.mu.Unlock()
+}
+
+// columnPosOf returns the position of the column that the world position passed is in.
+func columnPosOf(pos protocol.BlockPos) protocol.ColumnPos {
+	return protocol.ColumnPos{pos.X() >> 4, pos.Z() >> 4}
+}
+
+// localPos returns the world position passed relative to the column it is in.
+func localPos(pos protocol.BlockPos) protocol.BlockPos {
+	return protocol.BlockPos{pos.X() & 15, pos.Y(), pos.Z() & 15}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/justtaldevelops/expresso/expresso"
 	"github.com/justtaldevelops/expresso/expresso/protocol"
 	"github.com/justtaldevelops/expresso/expresso/protocol/packet"
+	"github.com/justtaldevelops/expresso/expresso/world"
 )
 
 func main() {
@@ -37,27 +38,20 @@ func handleConnection(conn *expresso.Connection) {
 	}
 
 	// Set the player's position and rotation.
-	err = conn.WritePacket(&packet.ServerPlayerPositionRotation{})
+	err = conn.WritePacket(&packet.ServerPlayerPositionRotation{Y: 4})
 	if err != nil {
 		panic(err)
 	}
 
-	// Initialize a new column.
-	column := protocol.NewColumn(protocol.ColumnPos{0, 0})
-	err = column.SetBlockState(protocol.BlockPos{0, 1, 0}, 10)
+	// Stream in superflat columns around the player, up to the view distance set in JoinGame.
+	generator, err := world.ParseFlatPreset(world.DefaultFlatPreset, world.DefaultBlockRegistry())
 	if err != nil {
 		panic(err)
 	}
-	err = column.SetBlockState(protocol.BlockPos{1, 3, 0}, 10)
-	if err != nil {
-		panic(err)
-	}
-
-	// Write the column data for this specific chunk.
-	err = conn.WritePacket(&packet.ChunkData{Column: column})
-
-	// Update the player's viewing column.
-	err = conn.WritePacket(&packet.UpdateViewPosition{})
+	view := expresso.NewChunkView(conn, expresso.ChunkSourceFunc(func(pos protocol.ColumnPos) (*protocol.Column, error) {
+		return generator.GenerateColumn(pos), nil
+	}), 16)
+	err = view.Move(protocol.ColumnPos{0, 0})
 	if err != nil {
 		panic(err)
 	}
@@ -72,6 +66,9 @@ func handleConnection(conn *expresso.Connection) {
 				break
 			}
 			fmt.Printf("%+v\n", pk)
+			if err := view.HandlePacket(pk); err != nil {
+				break
+			}
 		}
 	}()
 }